@@ -1,77 +1,10 @@
 package strength
 
 import (
+	"math"
 	"testing"
 )
 
-func Test_calcCubicEquation(t *testing.T) {
-	type args struct {
-		a float64
-		b float64
-		c float64
-		d float64
-	}
-	tests := []struct {
-		name    string
-		args    args
-		want    float64
-		want1   complex128
-		want2   complex128
-		wantErr bool
-	}{
-		{
-			name:    "Q >= 0",
-			args:    args{a: 1, b: 1, c: 1, d: 1},
-			want:    -1,
-			want1:   5.551115123125783e-17 + 0.9999999999999999i,
-			want2:   5.551115123125783e-17 - 0.9999999999999999i,
-			wantErr: false,
-		},
-		{
-			name:    "Q < 0",
-			args:    args{a: 1, b: -2, c: -1, d: 1},
-			want:    2.246979603717467,
-			want1:   0.5549581320873711 + 0i,
-			want2:   -0.8019377358048384 + 0i,
-			wantErr: false,
-		},
-		{
-			name:    "not cubic equation",
-			args:    args{a: 0, b: -2, c: -1, d: 1},
-			want:    0,
-			want1:   0 + 0i,
-			want2:   0 + 0i,
-			wantErr: true,
-		},
-		{
-			name:    "test exampl",
-			args:    args{a: 1, b: -0.24, c: 0, d: -1.57},
-			want:    1.2480088297460978,
-			want1:   -0.5040044148730489 + 1.0019897553251107i,
-			want2:   -0.5040044148730489 - 1.0019897553251107i,
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, got1, got2, err := calcCubicEquation(tt.args.a, tt.args.b, tt.args.c, tt.args.d)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("calcCubicEquation() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if got != tt.want {
-				t.Errorf("calcCubicEquation() got = %v, want %v", got, tt.want)
-			}
-			if got1 != tt.want1 {
-				t.Errorf("calcCubicEquation() got1 = %v, want %v", got1, tt.want1)
-			}
-			if got2 != tt.want2 {
-				t.Errorf("calcCubicEquation() got2 = %v, want %v", got2, tt.want2)
-			}
-		})
-	}
-}
-
 func Test_calcKappa(t *testing.T) {
 	type args struct {
 		relations float64
@@ -126,18 +59,18 @@ func TestApprox_calcRho(t *testing.T) {
 		{
 			name: "free plate press is zero",
 			a: Approx{
-				width:    120,
-				length:   60,
-				pressure: 0,
+				Width:    120,
+				Length:   60,
+				Pressure: 0,
 			},
 			want: 1,
 		},
 		{
 			name: "free plate press not zero",
 			a: Approx{
-				width:    120,
-				length:   60,
-				pressure: 1,
+				Width:    120,
+				Length:   60,
+				Pressure: 1,
 			},
 			want: 2.9299999999999997,
 		},
@@ -160,16 +93,16 @@ func TestApprox_calcStartCurvature(t *testing.T) {
 		{
 			name: "test 1",
 			a: Approx{
-				length:    60,
-				thickness: 10,
+				Length:    60,
+				Thickness: 10,
 			},
 			want: 0.55,
 		},
 		{
 			name: "test 2",
 			a: Approx{
-				length:    85.9,
-				thickness: 0.6,
+				Length:    85.9,
+				Thickness: 0.6,
 			},
 			want: 4.151833333333334,
 		},
@@ -193,18 +126,18 @@ func TestApprox_calcEulerianStrain(t *testing.T) {
 		{
 			name: "length > width",
 			a: Approx{
-				width:     60,
-				length:    120,
-				thickness: 0.6,
+				Width:     60,
+				Length:    120,
+				Thickness: 0.6,
 			},
 			want: 0.07600000000000001,
 		},
 		{
 			name: "length < width",
 			a: Approx{
-				width:     120,
-				length:    60,
-				thickness: 0.9,
+				Width:     120,
+				Length:    60,
+				Thickness: 0.9,
 			},
 			want: 0.06679687499999999,
 		},
@@ -233,10 +166,10 @@ func TestApprox_calcPressCurvature(t *testing.T) {
 		{
 			name: "curvature test",
 			a: Approx{
-				width:     120,
-				length:    60,
-				thickness: 0.6,
-				pressure:  60,
+				Width:     120,
+				Length:    60,
+				Thickness: 0.6,
+				Pressure:  60,
 			},
 			args: args{
 				k: kappa,
@@ -247,10 +180,10 @@ func TestApprox_calcPressCurvature(t *testing.T) {
 		{
 			name: "curvature zero press test",
 			a: Approx{
-				width:     120,
-				length:    60,
-				thickness: 0.6,
-				pressure:  0,
+				Width:     120,
+				Length:    60,
+				Thickness: 0.6,
+				Pressure:  0,
 			},
 			args: args{
 				k: kappa,
@@ -420,9 +353,9 @@ func TestApprox_calcX(t *testing.T) {
 		{
 			name: "first case",
 			a: Approx{
-				length:    60,
-				width:     120,
-				thickness: 5,
+				Length:    60,
+				Width:     120,
+				Thickness: 5,
 			},
 			args: args{
 				startCurv: 0.7,
@@ -436,9 +369,9 @@ func TestApprox_calcX(t *testing.T) {
 		{
 			name: "second case",
 			a: Approx{
-				length:    60,
-				width:     120,
-				thickness: 6,
+				Length:    60,
+				Width:     120,
+				Thickness: 6,
 			},
 			args: args{
 				startCurv: 0.6,
@@ -452,9 +385,9 @@ func TestApprox_calcX(t *testing.T) {
 		{
 			name: "tred case",
 			a: Approx{
-				length:    60,
-				width:     100,
-				thickness: 5.5,
+				Length:    60,
+				Width:     100,
+				Thickness: 5.5,
 			},
 			args: args{
 				startCurv: 0.6,
@@ -468,7 +401,8 @@ func TestApprox_calcX(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.a.calcX(tt.args.rho, tt.args.startCurv, tt.args.pressCurv, tt.args.eulStrain, tt.args.actStrain); got != tt.want {
+			// корни уточняются методом Ньютона, последние знаки могут отличаться от формулы Кардано
+			if got := tt.a.calcX(tt.args.rho, tt.args.startCurv, tt.args.pressCurv, tt.args.eulStrain, tt.args.actStrain); math.Abs(got-tt.want) > 1e-12*math.Abs(tt.want) {
 				t.Errorf("Approx.calcX() = %v, want %v", got, tt.want)
 			}
 		})
@@ -490,9 +424,9 @@ func TestApprox_calcReducing(t *testing.T) {
 		{
 			name: "first case",
 			a: Approx{
-				length:    60,
-				width:     59,
-				thickness: 6,
+				Length:    60,
+				Width:     59,
+				Thickness: 6,
 			},
 			args: args{
 				actStrain: -10.59118,
@@ -502,9 +436,9 @@ func TestApprox_calcReducing(t *testing.T) {
 		{
 			name: "second case",
 			a: Approx{
-				length:    60,
-				width:     120,
-				thickness: 6,
+				Length:    60,
+				Width:     120,
+				Thickness: 6,
 			},
 			args: args{
 				actStrain: -6.00167,
@@ -515,10 +449,10 @@ func TestApprox_calcReducing(t *testing.T) {
 		{
 			name: "tred case",
 			a: Approx{
-				length:    60,
-				width:     100,
-				thickness: 5.5,
-				pressure:  0.09,
+				Length:    60,
+				Width:     100,
+				Thickness: 5.5,
+				Pressure:  0.09,
 			},
 			args: args{
 				actStrain:    4.16783,
@@ -530,9 +464,83 @@ func TestApprox_calcReducing(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.a.calcReducing(tt.args.actStrain, tt.args.startCurv, tt.args.elasticModul); got != tt.want {
+			if got := tt.a.calcReducing(tt.args.actStrain, tt.args.startCurv, tt.args.elasticModul); math.Abs(got-tt.want) > 1e-12*math.Abs(tt.want) {
 				t.Errorf("Approx.calcReducing() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_createApprox(t *testing.T) {
+	tests := []struct {
+		name      string
+		plate     Flex
+		actStrain float64
+		want      Approx
+	}{
+		{
+			// σe = 7.6·(10·8/60)², f0 = 240/60·(1.5/8 + 0.4), φ = σe/20, площадь (60 - 30)·0.8·10
+			name:      "longitudinal compression",
+			plate:     Flex{ID: 1, Length: 240, Width: 60, ThicknessEnd: 8, Count: 10},
+			actStrain: -20,
+			want: Approx{
+				ID: 1, Length: 240, Width: 60, Thickness: 8, Count: 10, ActualStrain: -20,
+				EulerianStrain: 13.51111111111111,
+				StartCurvature: 2.35,
+				Reducing:       0.6755555555555556,
+				ReducingArea:   24,
+				AreaLoss:       77.86666666666666,
+			},
+		},
+		{
+			// σe = 1.9·(10·8/60)²·(1 + 60²/240²)², ρ = 4 - 2.81/4 + 1.34/16, κ = 0.0282 при отношении сторон 4,
+			// f0 = 1.5/8 + 0.4, fq = κ·50·60⁴/(2.06e8·0.8³), X корень x³ + k2·x² - k0 = 0,
+			// σц = ρ·(X - 1)·σe, φ = min(σe/20, σц/-20), площадь (240 - 30)·0.8·4
+			name:      "transverse with pressure",
+			plate:     Flex{ID: 2, Length: 60, Width: 240, ThicknessEnd: 8, Count: 4, Pressure: 50},
+			actStrain: -20,
+			want: Approx{
+				ID: 2, Length: 60, Width: 240, Thickness: 8, Count: 4, Pressure: 50, ActualStrain: -20,
+				EulerianStrain: 3.813194444444444,
+				Rho:            3.38125,
+				Kappa:          0.0282,
+				StartCurvature: 0.5875,
+				PressCurvature: 0.1732554611650485,
+				X:              0.6403518046465526,
+				ChainStrain:    -4.637074992235273,
+				Reducing:       0.19065972222222222,
+				ReducingArea:   168,
+				AreaLoss:       543.8766666666667,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := createApprox(&tt.plate, tt.actStrain, 2.06e8)
+			if got.ID != tt.want.ID || got.Length != tt.want.Length || got.Width != tt.want.Width || got.Thickness != tt.want.Thickness ||
+				got.Pressure != tt.want.Pressure || got.Count != tt.want.Count || got.ActualStrain != tt.want.ActualStrain {
+				t.Errorf("createApprox() plate data = %+v", got)
+			}
+			fields := []struct {
+				name      string
+				got, want float64
+			}{
+				{"EulerianStrain", got.EulerianStrain, tt.want.EulerianStrain},
+				{"Rho", got.Rho, tt.want.Rho},
+				{"Kappa", got.Kappa, tt.want.Kappa},
+				{"StartCurvature", got.StartCurvature, tt.want.StartCurvature},
+				{"PressCurvature", got.PressCurvature, tt.want.PressCurvature},
+				{"X", got.X, tt.want.X},
+				{"ChainStrain", got.ChainStrain, tt.want.ChainStrain},
+				{"Reducing", got.Reducing, tt.want.Reducing},
+				{"ReducingArea", got.ReducingArea, tt.want.ReducingArea},
+				{"AreaLoss", got.AreaLoss, tt.want.AreaLoss},
+			}
+			for _, f := range fields {
+				if math.Abs(f.got-f.want) > 1e-12*math.Max(1, math.Abs(f.want)) {
+					t.Errorf("createApprox() %s = %v, want %v", f.name, f.got, f.want)
+				}
+			}
+		})
+	}
+}
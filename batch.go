@@ -0,0 +1,110 @@
+package strength
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Model исходные данные одного расчёта пакета.
+// Связи должны быть предварительно посчитаны CalcAllRigid и CalcAllFlex.
+type Model struct {
+	Name     string        // имя расчёта для сообщений об ошибках
+	BaseData *BaseData     // исходные данные
+	Rigid    map[int]Rigid // жёсткие связи
+	Flex     map[int]Flex  // гибкие связи
+	Options  *Options      // параметры итераций, может быть nil
+}
+
+// BatchRezult результат одного расчёта пакета.
+type BatchRezult struct {
+	Name        string      // имя расчёта
+	Iterations  Iterations  // приближения
+	Convergence Convergence // сведения о сходимости
+	Err         error       // ошибка расчёта
+}
+
+// ModelError ошибка одного расчёта пакета.
+type ModelError struct {
+	Index int    // номер расчёта в пакете
+	Name  string // имя расчёта
+	Err   error  // ошибка
+}
+
+// Error описание ошибки.
+func (e *ModelError) Error() string {
+	return fmt.Sprintf("model %d %q: %v", e.Index, e.Name, e.Err)
+}
+
+// BatchError ошибки всех неудавшихся расчётов пакета.
+type BatchError []ModelError
+
+// Error перечисление ошибок.
+func (e BatchError) Error() string {
+	rez := make([]string, len(e))
+	for key := range e {
+		rez[key] = e[key].Error()
+	}
+	return fmt.Sprintf("%d of batch failed: %s", len(e), strings.Join(rez, "; "))
+}
+
+// CalculateBatch считает независимые расчёты параллельно, не более concurrency одновременно,
+// если 0 то по числу процессоров. Результаты идут в порядке расчётов.
+// Ошибки отдельных расчётов собираются в BatchError, остальные расчёты при этом продолжаются.
+// Отмена ctx прерывает все незавершённые расчёты.
+func CalculateBatch(ctx context.Context, models []Model, concurrency int) ([]BatchRezult, error) {
+	workers := concurrency
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	rez := make([]BatchRezult, len(models))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range jobs {
+				rez[key] = calculateModel(ctx, &models[key])
+			}
+		}()
+	}
+	for key := range models {
+		jobs <- key
+	}
+	close(jobs)
+	wg.Wait()
+
+	var errs BatchError
+	for key, val := range rez {
+		if val.Err != nil {
+			errs = append(errs, ModelError{Index: key, Name: val.Name, Err: val.Err})
+		}
+	}
+	if errs != nil {
+		return rez, errs
+	}
+	return rez, nil
+}
+
+// calculateModel считает один расчёт пакета.
+func calculateModel(ctx context.Context, m *Model) BatchRezult {
+	rez := BatchRezult{Name: m.Name}
+	if m.BaseData == nil {
+		rez.Err = fmt.Errorf("no base data")
+		return rez
+	}
+	if len(m.Rigid)+len(m.Flex) == 0 {
+		rez.Err = fmt.Errorf("no elements")
+		return rez
+	}
+	opts := m.Options
+	if opts == nil {
+		opts = &Options{}
+	}
+	rez.Iterations, rez.Convergence, rez.Err = CalculateContext(ctx, m.BaseData, m.Rigid, m.Flex, opts)
+	return rez
+}
@@ -0,0 +1,56 @@
+package strength
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCalculateBatch(t *testing.T) {
+	base, rigid, flex := convergenceModel()
+	moments := []float64{1e5, 2e5, 3e5, 4e5, 0}
+	models := make([]Model, 0, len(moments)+1)
+	for _, val := range moments {
+		b := base
+		b.Moment = val
+		models = append(models, Model{BaseData: &b, Rigid: rigid, Flex: flex, Options: &Options{Workers: 2}})
+	}
+	models = append(models, Model{Name: "пустой", Rigid: rigid, Flex: flex})
+
+	rez, err := CalculateBatch(context.Background(), models, 3)
+	errs, ok := err.(BatchError)
+	if !ok || len(errs) != 1 || errs[0].Index != len(moments) || errs[0].Name != "пустой" {
+		t.Fatalf("CalculateBatch() error = %v", err)
+	}
+	for key, val := range moments {
+		b := base
+		b.Moment = val
+		want := Calculate(&b, rigid, flex)
+		if rez[key].Err != nil || !reflect.DeepEqual(rez[key].Iterations, want) {
+			t.Errorf("CalculateBatch() model %d differs from Calculate, error %v", key, rez[key].Err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CalculateBatch(ctx, models[:2], 0); err == nil {
+		t.Errorf("CalculateBatch() cancelled error = nil")
+	}
+}
+
+func Test_createAllApprox_workers(t *testing.T) {
+	base, rigid, flex := convergenceModel()
+	members := buildMembers(rigid, flex, base.Symmetry)
+	sec := calcMemberSums(members)
+	central := sec.central()
+	want, err := createAllApprox(context.Background(), members, &central, base.Moment, base.ElasticModul, base.MomentFlag, 1)
+	if err != nil {
+		t.Fatalf("createAllApprox() error = %v", err)
+	}
+	for _, workers := range []int{2, 4, 16} {
+		got, err := createAllApprox(context.Background(), members, &central, base.Moment, base.ElasticModul, base.MomentFlag, workers)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("createAllApprox() with %d workers differs, error %v", workers, err)
+		}
+	}
+}
@@ -4,32 +4,133 @@ import (
 	"fmt"
 	"math"
 	"os"
-	"strconv"
 
 	str "github.com/kenits/strength"
+	"github.com/kenits/strength/model"
 
 	excel "github.com/360EntSecGroup-Skylar/excelize/v2"
 )
 
 func main() {
-	args := os.Args
-	if len(args) == 1 {
-		fmt.Println("Необходимо имя файла")
-		return
-	}
-	fileName := args[1]
-	err := calc(fileName)
-	if err != nil {
-		fmt.Println("Что-то пошло не так")
-		fmt.Println(err)
-		return
+	os.Exit(run(os.Args[1:]))
+}
+
+// calcFile считает книгу Excel или модель JSON/YAML по расширению файла.
+func calcFile(fileName string, opt *options) error {
+	if _, ok := model.FormatOf(fileName); ok {
+		if opt.sensitivity {
+			return usageError("чувствительность записывается только в книгу Excel")
+		}
+		return calcModel(fileName, opt)
 	}
-	fmt.Println("Отработано")
+	return calc(fileName, opt)
+}
 
+// baseSheet лист исходных данных.
+const baseSheet = "Исходные данные"
+
+// baseParams подписи исходных данных, значение в ячейке справа от подписи.
+var baseParams = []model.Column{
+	{Name: "project", Aliases: []string{"проект"}},
+	{Name: "name", Aliases: []string{"имя расчёта", "расчёт", "имя"}},
+	{Name: "age", Aliases: []string{"срок службы"}, Required: true},
+	{Name: "moment", Aliases: []string{"расчётный момент", "момент"}},
+	{Name: "bending", Aliases: []string{"изгиб", "прогиб/перегиб"}, Required: true},
+	{Name: "symmetry", Aliases: []string{"симметрия"}},
+	{Name: "accuracy", Aliases: []string{"точность"}, Required: true},
+	{Name: "elastic_modul", Aliases: []string{"модуль упругости", "E"}, Required: true},
 }
 
+// Имена таблиц Excel и именованных областей с таблицами исходных данных.
+var (
+	pointsNames = model.Column{Name: "points", Aliases: []string{"расчётные точки", "точки"}}
+	rigidNames  = model.Column{Name: "rigid", Aliases: []string{"жёсткие связи"}}
+	flexNames   = model.Column{Name: "flex", Aliases: []string{"гибкие связи"}}
+)
+
+// readBaseData читает исходные данные по подписям на листе, расчётные точки по заголовку таблицы.
+// Книги без подписей читаются по фиксированным ячейкам.
+// Ошибки значений листа собираются в model.InputErrors.
 func readBaseData(file *excel.File) (*str.BaseData, error) {
-	nameSheet := "Исходные данные"
+	params, err := findParams(file, baseSheet, baseParams)
+	var errs model.InputErrors
+	if err = model.Collect(&errs, err); err != nil {
+		return nil, err
+	}
+	if params == nil {
+		return readBaseDataCells(file)
+	}
+
+	p := newSheetParser(file, baseSheet)
+	parse := func(name string) float64 {
+		if params[name].value == "" {
+			p.fail(params[name].cell, name, "", "number")
+			return 0
+		}
+		return p.float(params[name].cell, name, params[name].value, 0)
+	}
+	data := str.BaseData{
+		Project: params["project"].value,
+		Name:    params["name"].value,
+	}
+	if _, ok := params["age"]; ok {
+		data.Age = parse("age")
+	}
+	if val := params["moment"]; val.value != "" {
+		data.Moment = math.Abs(p.float(val.cell, "moment", val.value, 0))
+	}
+	switch val := params["bending"]; val.value {
+	case "перегиб", "hogging":
+		data.MomentFlag = true
+	case "прогиб", "sagging", "":
+	default:
+		p.fail(val.cell, "bending", val.value, `"прогиб" or "перегиб"`)
+	}
+	switch val := params["symmetry"]; val.value {
+	case "да", "yes":
+		data.Symmetry = true
+	case "нет", "no", "":
+	default:
+		p.fail(val.cell, "symmetry", val.value, `"да" or "нет"`)
+	}
+	if _, ok := params["accuracy"]; ok {
+		data.Accuracy = parse("accuracy")
+	}
+	if _, ok := params["elastic_modul"]; ok {
+		data.ElasticModul = parse("elastic_modul")
+	}
+	errs = append(errs, p.errs...)
+
+	reg, err := findTable(file, baseSheet, &pointsNames, model.PointColumns)
+	if err = model.Collect(&errs, err); err != nil {
+		return nil, err
+	}
+	if reg != nil {
+		points, err := model.PointTable(reg.records)
+		if e, ok := err.(model.InputErrors); ok {
+			e.Locate(reg.sheet, reg.row, reg.col)
+		}
+		if err = model.Collect(&errs, err); err != nil {
+			return nil, err
+		}
+		for _, val := range points {
+			data.Height = append(data.Height, val.Height)
+			data.Strain = append(data.Strain, val.Strain)
+		}
+		if err == nil && len(points) == 0 {
+			errs = append(errs, model.InputError{Sheet: reg.sheet, Row: reg.row + 1, Expected: "points"})
+		}
+	}
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return &data, nil
+}
+
+// readBaseDataCells читает исходные данные книг старого образца без подписей:
+// значения в фиксированных ячейках B1..B9, расчётные точки со строки 13.
+func readBaseDataCells(file *excel.File) (*str.BaseData, error) {
+	p := newSheetParser(file, baseSheet)
 
 	addr := [8]string{
 		"B1",
@@ -45,7 +146,7 @@ func readBaseData(file *excel.File) (*str.BaseData, error) {
 	rez := [8]string{}
 
 	for key, val := range addr {
-		data, err := file.GetCellValue(nameSheet, val)
+		data, err := p.cell(val)
 		if err != nil {
 			return nil, err
 		}
@@ -63,40 +164,38 @@ func readBaseData(file *excel.File) (*str.BaseData, error) {
 		symmetry = true
 	}
 
-	age, err := strconv.ParseFloat(rez[2], 64)
-	if err != nil {
-		return nil, err
-	}
-	elasticModul, err := strconv.ParseFloat(rez[7], 64)
-	if err != nil {
-		return nil, err
-	}
-	moment := 0.0
-	if rez[3] != "" {
-		moment, err = strconv.ParseFloat(rez[3], 64)
-		if err != nil {
-			return nil, err
+	// обязательные значения: пустая ячейка тоже ошибка
+	required := func(key int, field string) float64 {
+		if rez[key] == "" {
+			p.fail(addr[key], field, "", "number")
+			return 0
 		}
-		moment = math.Abs(moment)
+		return p.float(addr[key], field, rez[key], 0)
 	}
+	age := required(2, "age")
+	elasticModul := required(7, "elastic_modul")
+	moment := math.Abs(p.float(addr[3], "moment", rez[3], 0))
+	accuracy := required(6, "accuracy")
 
-	accuracy, err := strconv.ParseFloat(rez[6], 64)
+	height, err := p.floats("A", 13, "height")
 	if err != nil {
 		return nil, err
 	}
 
-	height, err := readVerticalArrayFloat(nameSheet, "A", 13, file)
-	if err != nil {
-		return nil, err
-	}
-
-	strain, err := readVerticalArrayFloat(nameSheet, "B", 13, file)
+	strain, err := p.floats("B", 13, "strain")
 	if err != nil {
 		return nil, err
 	}
 
 	if len(height) != len(strain) {
-		return nil, fmt.Errorf("missing height/strain")
+		row := 13 + len(height)
+		if len(strain) < len(height) {
+			row = 13 + len(strain)
+		}
+		p.errs = append(p.errs, model.InputError{Sheet: baseSheet, Row: row, Expected: "height/strain"})
+	}
+	if err = p.err(); err != nil {
+		return nil, err
 	}
 
 	data := str.BaseData{
@@ -115,140 +214,95 @@ func readBaseData(file *excel.File) (*str.BaseData, error) {
 
 }
 
-func readRigid(file *excel.File) (map[int]str.Rigid, error) {
-	nameSheet := "Жёсткие связи"
-
-	id, err := readVerticalArrayInt(nameSheet, "A", 2, file)
+// readRigid читает таблицу жёстких связей, столбцы находятся по заголовку.
+// Ошибки значений с листом и ячейкой возвращаются в model.InputErrors.
+func readRigid(file *excel.File) (map[int]str.Rigid, *elementTable, error) {
+	reg, err := findTable(file, "Жёсткие связи", &rigidNames, model.RigidColumns)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	name, err := readVerticalArray(nameSheet, "B", 2, file)
-	if err != nil {
-		return nil, err
+	data, err := model.RigidTable(reg.records)
+	if errs, ok := err.(model.InputErrors); ok {
+		errs.Locate(reg.sheet, reg.row, reg.col)
+		return nil, nil, errs
 	}
-	area, err := readVerticalArrayFloat(nameSheet, "C", 2, file)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	corrosion, err := readVerticalArrayFloat(nameSheet, "D", 2, file)
+	doc := model.Document{Rigid: data}
+	rigid, _, err := doc.Elements()
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("%s: %v", reg.sheet, err)
 	}
-	heigth, err := readVerticalArrayFloat(nameSheet, "E", 2, file)
+	table, err := newElementTable(reg, model.RigidColumns)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	count, err := readVerticalArrayFloat(nameSheet, "F", 2, file)
-	if err != nil {
-		return nil, err
-	}
-	rigidMap := make(map[int]str.Rigid)
-	for key := range id {
-		rigid := str.Rigid{
-			ID:        id[key],
-			Name:      name[key],
-			AreaStart: area[key],
-			Corrosion: corrosion[key],
-			Height:    heigth[key],
-			Count:     count[key],
-		}
-		rigidMap[rigid.ID] = rigid
-
-	}
-
-	return rigidMap, nil
-
+	return rigid, table, nil
 }
-func readFlex(file *excel.File) (map[int]str.Flex, error) {
-	nameSheet := "Гибкие связи"
 
-	id, err := readVerticalArrayInt(nameSheet, "A", 2, file)
+// readFlex читает таблицу гибких связей, столбцы находятся по заголовку.
+// Ошибки значений с листом и ячейкой возвращаются в model.InputErrors.
+func readFlex(file *excel.File) (map[int]str.Flex, *elementTable, error) {
+	reg, err := findTable(file, "Гибкие связи", &flexNames, model.FlexColumns)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	name, err := readVerticalArray(nameSheet, "B", 2, file)
-	if err != nil {
-		return nil, err
+	data, err := model.FlexTable(reg.records)
+	if errs, ok := err.(model.InputErrors); ok {
+		errs.Locate(reg.sheet, reg.row, reg.col)
+		return nil, nil, errs
 	}
-	length, err := readVerticalArrayFloat(nameSheet, "C", 2, file)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	width, err := readVerticalArrayFloat(nameSheet, "D", 2, file)
+	doc := model.Document{Flex: data}
+	_, flex, err := doc.Elements()
 	if err != nil {
-		return nil, err
-	}
-	thickness, err := readVerticalArrayFloat(nameSheet, "E", 2, file)
-	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("%s: %v", reg.sheet, err)
 	}
-	corrosion, err := readVerticalArrayFloat(nameSheet, "F", 2, file)
+	table, err := newElementTable(reg, model.FlexColumns)
 	if err != nil {
-		return nil, err
-	}
-	heigth, err := readVerticalArrayFloat(nameSheet, "G", 2, file)
-	if err != nil {
-		return nil, err
-	}
-	count, err := readVerticalArrayFloat(nameSheet, "H", 2, file)
-	if err != nil {
-		return nil, err
-	}
-	press, err := readVerticalArrayFloat(nameSheet, "I", 2, file)
-	if err != nil {
-		return nil, err
-	}
-
-	flexMap := make(map[int]str.Flex)
-	for key := range id {
-		flex := str.Flex{
-			ID:             id[key],
-			Name:           name[key],
-			Length:         length[key],
-			Width:          width[key],
-			ThicknessStart: thickness[key],
-			Corrosion:      corrosion[key],
-			Height:         heigth[key],
-			Count:          count[key],
-			Pressure:       press[key],
-		}
-		flexMap[flex.ID] = flex
-
+		return nil, nil, err
 	}
-
-	return flexMap, nil
-
+	return flex, table, nil
 }
 
-func writeRigid(rigid map[int]str.Rigid, file *excel.File) error {
-	nameSheet := "Жёсткие связи"
-	for _, val := range rigid {
-		err := writeRigidRow(nameSheet, &val, file)
+// writeRigid пишет площадь, статический и момент инерции жёстких связей справа от таблицы.
+func writeRigid(rigid map[int]str.Rigid, table *elementTable, file *excel.File) error {
+	err := table.writeHead([]string{"Площадь в конце срока службы", "Статический момент", "Момент инерции"}, file)
+	if err != nil {
+		return err
+	}
+	for _, id := range str.RigidIDs(rigid) {
+		val := rigid[id]
+		err = table.writeRow(id, []interface{}{val.AreaEnd, val.StaticMoment, val.MomentOfInertia}, file)
 		if err != nil {
 			return err
 		}
 	}
-
 	return nil
-
 }
-func writeFlex(flex map[int]str.Flex, file *excel.File) error {
-	nameSheet := "Гибкие связи"
 
-	for _, val := range flex {
-		err := writeFlexRow(nameSheet, &val, file)
+// writeFlex пишет площадь, статический и момент инерции гибких связей справа от таблицы.
+func writeFlex(flex map[int]str.Flex, table *elementTable, file *excel.File) error {
+	err := table.writeHead([]string{"Площадь в конце срока службы", "Статический момент", "Момент инерции"}, file)
+	if err != nil {
+		return err
+	}
+	for _, id := range str.FlexIDs(flex) {
+		val := flex[id]
+		err = table.writeRow(id, []interface{}{val.AreaEnd, val.StaticMoment, val.MomentOfInertia}, file)
 		if err != nil {
 			return err
 		}
 	}
-
 	return nil
-
 }
 
-func writeAllRezult(rezult map[int]str.Rezult, file *excel.File) error {
-	for key, val := range rezult {
-		err := writeRezult(key, &val, file)
+func writeAllRezult(data str.Iterations, file *excel.File) error {
+	for _, val := range data {
+		err := writeRezult(val.ID, &val.Rezult, file)
 		if err != nil {
 			return err
 		}
@@ -256,9 +310,9 @@ func writeAllRezult(rezult map[int]str.Rezult, file *excel.File) error {
 	return nil
 }
 
-func writeAllApprox(approx map[int]map[int]str.Approx, file *excel.File) error {
-	for key, val := range approx {
-		err := writeApprox(key, val, file)
+func writeAllApprox(data str.Iterations, file *excel.File) error {
+	for _, val := range data[1:] {
+		err := writeApprox(val.ID, val.Approx, file)
 		if err != nil {
 			return err
 		}
@@ -267,141 +321,222 @@ func writeAllApprox(approx map[int]map[int]str.Approx, file *excel.File) error {
 
 }
 
-// calc сосчитать файл
-func calc(fileName string) error {
+// input исходные данные книги.
+type input struct {
+	baseData   *str.BaseData
+	rigid      map[int]str.Rigid
+	rigidTable *elementTable
+	flex       map[int]str.Flex
+	flexTable  *elementTable
+
+	loadCase       *str.LoadCase             // nil если нет листа нагрузок
+	gauging        *str.GaugingData          // nil если нет листа замеров
+	support        str.Support               // закрепление для изгиба корпуса
+	stations       []str.Station             // nil если нет листа изгиба
+	fatigueLoad    *str.FatigueLoad          // nil если нет листа усталости
+	fatigueDetails map[int]str.FatigueDetail // узлы для усталости
+}
 
-	file, err := excel.OpenFile(fileName)
-	if err != nil {
-		return err
+// readInput читает все листы исходных данных до расчёта.
+// Ошибки значений всех листов собираются в model.InputErrors, чтобы показать их разом.
+func readInput(file *excel.File) (*input, error) {
+	var (
+		in   input
+		errs model.InputErrors
+		err  error
+	)
+	in.baseData, err = readBaseData(file)
+	if err = model.Collect(&errs, err); err != nil {
+		return nil, err
+	}
+	in.rigid, in.rigidTable, err = readRigid(file)
+	if err = model.Collect(&errs, err); err != nil {
+		return nil, err
+	}
+	in.flex, in.flexTable, err = readFlex(file)
+	if err = model.Collect(&errs, err); err != nil {
+		return nil, err
+	}
+	if file.GetSheetIndex(pressureSheet) != 0 {
+		in.loadCase, err = readLoadCase(file)
+		if err = model.Collect(&errs, err); err != nil {
+			return nil, err
+		}
 	}
+	if file.GetSheetIndex(gaugingSheet) != 0 {
+		in.gauging, err = readGauging(file)
+		if err = model.Collect(&errs, err); err != nil {
+			return nil, err
+		}
+	}
+	if file.GetSheetIndex(deflectionSheet) != 0 {
+		in.support, err = readSupport(file)
+		if err = model.Collect(&errs, err); err != nil {
+			return nil, err
+		}
+		in.stations, err = readStations(file)
+		if err = model.Collect(&errs, err); err != nil {
+			return nil, err
+		}
+	}
+	if file.GetSheetIndex(fatigueSheet) != 0 {
+		in.fatigueLoad, err = readFatigueLoad(file)
+		if err = model.Collect(&errs, err); err != nil {
+			return nil, err
+		}
+		in.fatigueDetails, err = readFatigueDetails(file)
+		if err = model.Collect(&errs, err); err != nil {
+			return nil, err
+		}
+	}
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return &in, nil
+}
 
-	basedata, err := readBaseData(file)
+// calc сосчитать книгу и записать её с результатами в rezult.xlsx, в файл -o или в исходную книгу при -i.
+func calc(fileName string, opt *options) error {
+	output := "rezult.xlsx"
+	switch {
+	case opt.inPlace:
+		output = fileName
+	case opt.output != "":
+		output = opt.output
+	}
+	if _, ok := model.FormatOf(output); ok {
+		return usageError("результаты книги пишутся в книгу Excel, для модели используйте convert")
+	}
+
+	file, err := excel.OpenFile(fileName)
 	if err != nil {
 		return err
 	}
 
-	rigid, err := readRigid(file)
+	in, err := readInput(file)
 	if err != nil {
 		return err
 	}
+	// Чувствительность считается по скорости коррозии, с замерами толщин она не имеет смысла
+	if opt.sensitivity && in.gauging != nil {
+		return usageError("чувствительность не считается для книги с замерами толщин")
+	}
+	basedata, rigid, flex := in.baseData, in.rigid, in.flex
+	if opt.accuracy != 0 {
+		basedata.Accuracy = opt.accuracy
+	}
+	// В книге один случай загрузки на листе нагрузок
+	if opt.loadCase != "" && (in.loadCase == nil || in.loadCase.Name != opt.loadCase) {
+		return fmt.Errorf("missing load case %q", opt.loadCase)
+	}
 
 	str.CalcAllRigid(rigid, basedata.Age)
-	err = writeRigid(rigid, file)
-	if err != nil {
-		return err
+	str.CalcAllFlex(flex, basedata.Age)
+
+	// Если есть лист нагрузок, давления на пластины считаются по случаю загрузки
+	if in.loadCase != nil {
+		err = str.ApplyPressure(in.loadCase, flex)
+		if err != nil {
+			return err
+		}
+		err = writePressure(flex, file)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Если есть лист замеров, считаем по фактическим толщинам
+	if in.gauging != nil {
+		err = str.ApplyGauging(in.gauging, rigid, flex)
+		if err != nil {
+			return err
+		}
+		err = writeGauging(in.gauging, file)
+		if err != nil {
+			return err
+		}
 	}
 
-	flex, err := readFlex(file)
+	err = writeRigid(rigid, in.rigidTable, file)
 	if err != nil {
 		return err
 	}
 
-	str.CalcAllFlex(flex, basedata.Age)
-	err = writeFlex(flex, file)
+	err = writeFlex(flex, in.flexTable, file)
 	if err != nil {
 		return err
 	}
 
-	approx, rezult := str.Calculate(basedata, rigid, flex)
+	rezult, conv, err := str.CalculateWithOptions(basedata, rigid, flex, opt.calcOptions())
 	if err != nil {
 		return err
 	}
+	if !opt.quiet {
+		printConvergence(&conv, basedata.Accuracy)
+	}
 
 	err = writeAllRezult(rezult, file)
 	if err != nil {
 		return err
 	}
 
-	err = writeAllApprox(approx, file)
+	err = writeAllApprox(rezult, file)
 	if err != nil {
 		return err
 	}
-	file.SaveAs("rezult.xlsx")
-
-	return nil
-}
-func readVerticalArrayFloat(sheetName, column string, row int, file *excel.File) ([]float64, error) {
 
-	arr := make([]float64, 0)
-	data, err := readVerticalArray(sheetName, column, row, file)
+	err = writeConvergence(&conv, basedata.Height, file)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	for _, val := range data {
-		floatVal, err := strconv.ParseFloat(val, 64)
+
+	last := rezult.Last()
+	if in.stations != nil {
+		err = calcDeflection(basedata, &last, in.support, in.stations, file)
 		if err != nil {
-			return arr, err
+			return err
 		}
-		arr = append(arr, floatVal)
 	}
-	return arr, nil
-
-}
 
-func readVerticalArrayInt(sheetName, column string, row int, file *excel.File) ([]int, error) {
-
-	arr := make([]int, 0)
-	data, err := readVerticalArray(sheetName, column, row, file)
-	if err != nil {
-		return nil, err
+	if in.fatigueLoad != nil {
+		err = calcFatigue(&last, rigid, in.fatigueLoad, in.fatigueDetails, file)
+		if err != nil {
+			return err
+		}
 	}
-	for _, val := range data {
-		intVal, err := strconv.ParseInt(val, 10, 32)
+
+	// Чувствительность по флагу -sensitivity: по два расчёта на каждый параметр каждой связи
+	if opt.sensitivity {
+		sensitivity, err := str.CalcSensitivity(basedata, rigid, flex, 0)
 		if err != nil {
-			return arr, err
+			return err
+		}
+		err = writeSensitivity(sensitivity, basedata.Height, file)
+		if err != nil {
+			return err
 		}
-		arr = append(arr, int(intVal))
 	}
-	return arr, nil
+	return file.SaveAs(output)
 }
 
-func writeRigidRow(sheetName string, rigid *str.Rigid, file *excel.File) error {
-	var (
-		err error
-	)
-	row := rigid.ID + 1
-
-	addrArea := fmt.Sprintf("G%d", row)
-	addrStaticMoment := fmt.Sprintf("H%d", row)
-	addrMomentOfInertia := fmt.Sprintf("I%d", row)
-	err = file.SetCellValue(sheetName, addrArea, rigid.AreaEnd)
-	if err != nil {
-		return err
-	}
-	err = file.SetCellValue(sheetName, addrStaticMoment, rigid.StaticMoment)
-	if err != nil {
-		return err
-	}
-	err = file.SetCellValue(sheetName, addrMomentOfInertia, rigid.MomentOfInertia)
+// readVerticalArrayFloat читает числа столбца до первой пустой ячейки, ошибки значений в model.InputErrors.
+func readVerticalArrayFloat(sheetName, column string, row int, file *excel.File) ([]float64, error) {
+	p := newSheetParser(file, sheetName)
+	arr, err := p.floats(column, row, "")
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
-
+	return arr, p.err()
 }
 
-func writeFlexRow(sheetName string, flex *str.Flex, file *excel.File) error {
-	var (
-		err error
-	)
-	row := flex.ID + 1
-	addrArea := fmt.Sprintf("J%d", row)
-	addrStaticMoment := fmt.Sprintf("K%d", row)
-	addrMomentOfInertia := fmt.Sprintf("L%d", row)
-	err = file.SetCellValue(sheetName, addrArea, flex.AreaEnd)
-	if err != nil {
-		return err
-	}
-	err = file.SetCellValue(sheetName, addrStaticMoment, flex.StaticMoment)
-	if err != nil {
-		return err
-	}
-	err = file.SetCellValue(sheetName, addrMomentOfInertia, flex.MomentOfInertia)
+// readVerticalArrayInt читает целые столбца до первой пустой ячейки, ошибки значений в model.InputErrors.
+func readVerticalArrayInt(sheetName, column string, row int, file *excel.File) ([]int, error) {
+	p := newSheetParser(file, sheetName)
+	arr, err := p.ints(column, row, "")
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
-
+	return arr, p.err()
 }
 
 func writeRezult(id int, rezult *str.Rezult, file *excel.File) error {
@@ -434,6 +569,34 @@ func writeRezult(id int, rezult *str.Rezult, file *excel.File) error {
 		return fmt.Errorf("not simetry write")
 	}
 
+	// Для несимметричного сечения характеристики относительно вертикальной оси и главные оси
+	if rezult.ProductOfInertia != 0 || rezult.HalfBreadth != 0 {
+		names = []string{
+			"Полуширота центра масс",
+			"Момент инерции относительно вертикальной оси",
+			"Центробежный момент инерции",
+			"Угол нейтральной оси",
+			"Угол главной оси",
+			"Главный момент инерции",
+		}
+		vals = []float64{
+			rezult.HalfBreadth,
+			rezult.TransverseInertia,
+			rezult.ProductOfInertia,
+			rezult.NeutralAxisAngle,
+			rezult.PrincipalAngle,
+			rezult.PrincipalInertia,
+		}
+		_, err = writeVerticalArrayStrings(sheetName, "D", 1, names, file)
+		if err != nil {
+			return err
+		}
+		_, err = writeVerticalArrayFloat(sheetName, "E", 1, vals, file)
+		if err != nil {
+			return err
+		}
+	}
+
 	tableStartRow := 7
 	err = file.SetCellValue(sheetName, fmt.Sprintf("A%d", tableStartRow), "Высота")
 	if err != nil {
@@ -475,11 +638,10 @@ func writeRezult(id int, rezult *str.Rezult, file *excel.File) error {
 	return nil
 }
 
-func writeApproxRow(sheetName string, approx *str.Approx, file *excel.File) error {
+func writeApproxRow(sheetName string, row int, approx *str.Approx, file *excel.File) error {
 	var (
 		err error
 	)
-	row := approx.ID + 1
 	addrID := fmt.Sprintf("A%d", row)
 	addrReducing := fmt.Sprintf("B%d", row)
 	addrReverseReducing := fmt.Sprintf("C%d", row)
@@ -520,19 +682,41 @@ func writeApproxRow(sheetName string, approx *str.Approx, file *excel.File) erro
 	if err != nil {
 		return err
 	}
+
+	// промежуточные величины для проверки редукционного коэффициента вручную
+	details := []interface{}{
+		approx.HalfBreadth,
+		approx.Length,
+		approx.Width,
+		approx.Thickness,
+		approx.Pressure,
+		approx.Count,
+		approx.ActualStrain,
+		approx.EulerianStrain,
+		approx.ChainStrain,
+		approx.Rho,
+		approx.Kappa,
+		approx.StartCurvature,
+		approx.PressCurvature,
+		approx.X,
+	}
+	err = file.SetSheetRow(sheetName, fmt.Sprintf("I%d", row), &details)
+	if err != nil {
+		return err
+	}
 	return nil
 
 }
 
-func writeApprox(id int, approx map[int]str.Approx, file *excel.File) error {
+func writeApprox(id int, approx []str.Approx, file *excel.File) error {
 	sheetName := fmt.Sprintf("Приближение %d", id)
 	file.NewSheet(sheetName)
 	err := writeApproxHead(sheetName, file)
 	if err != nil {
 		return err
 	}
-	for _, val := range approx {
-		err = writeApproxRow(sheetName, &val, file)
+	for key, val := range approx {
+		err = writeApproxRow(sheetName, key+2, &val, file)
 		if err != nil {
 			return err
 		}
@@ -575,6 +759,26 @@ func writeApproxHead(sheetName string, file *excel.File) error {
 	if err != nil {
 		return err
 	}
+	details := []string{
+		"Полуширота",
+		"Длина",
+		"Ширина",
+		"Толщина",
+		"Давление",
+		"Количество",
+		"Действующее напряжение",
+		"Эйлерово напряжение",
+		"Цепное напряжение",
+		"rho",
+		"kappa",
+		"Начальная погибь",
+		"Стрелка от давления",
+		"X",
+	}
+	err = file.SetSheetRow(sheetName, "I1", &details)
+	if err != nil {
+		return err
+	}
 	return nil
 
 }
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	str "github.com/kenits/strength"
+)
+
+// Коды выхода.
+const (
+	exitOK      = 0 // успешно
+	exitFailure = 1 // ошибка исходных данных или расчёта
+	exitUsage   = 2 // неверные аргументы командной строки
+)
+
+// usageError ошибка в аргументах командной строки.
+type usageError string
+
+// Error текст ошибки.
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// options общие флаги команд.
+type options struct {
+	output      string  // путь результата
+	inPlace     bool    // записать результаты в исходную книгу
+	loadCase    string  // имя случая загрузки, пусто для всех
+	accuracy    float64 // точность вместо заданной в исходных данных, 0 если не задана
+	verbose     bool    // печатать каждое приближение
+	quiet       bool    // печатать только ошибки
+	force       bool    // перезаписывать существующий файл
+	full        bool    // шаблон со всеми листами
+	sensitivity bool    // посчитать чувствительность к параметрам связей
+	sweep       sweepRange
+}
+
+// Флаги команд.
+const (
+	flagOutput = 1 << iota
+	flagInPlace
+	flagLoadCase
+	flagAccuracy
+	flagVerbose
+	flagForce
+	flagSweep
+	flagFull
+	flagSensitivity
+)
+
+// register добавляет в набор флаги mask.
+func (o *options) register(fs *flag.FlagSet, mask int, output string) {
+	if mask&flagOutput != 0 {
+		fs.StringVar(&o.output, "o", "", output)
+	}
+	if mask&flagInPlace != 0 {
+		fs.BoolVar(&o.inPlace, "i", false, "записать результаты в исходную книгу")
+	}
+	if mask&flagLoadCase != 0 {
+		fs.StringVar(&o.loadCase, "case", "", "считать только случай загрузки с этим именем")
+	}
+	if mask&flagAccuracy != 0 {
+		fs.Float64Var(&o.accuracy, "accuracy", 0, "точность % вместо заданной в исходных данных")
+	}
+	if mask&flagVerbose != 0 {
+		fs.BoolVar(&o.verbose, "v", false, "печатать каждое приближение")
+	}
+	if mask&flagForce != 0 {
+		fs.BoolVar(&o.force, "f", false, "перезаписать существующий файл")
+	}
+	if mask&flagSweep != 0 {
+		fs.StringVar(&o.sweep.param, "param", "age", "параметр ряда: age срок службы или moment расчётный момент")
+		fs.Float64Var(&o.sweep.from, "from", 0, "первое значение")
+		fs.Float64Var(&o.sweep.to, "to", 0, "последнее значение")
+		fs.Float64Var(&o.sweep.step, "step", 1, "шаг")
+	}
+	if mask&flagFull != 0 {
+		fs.BoolVar(&o.full, "full", false, "добавить листы нагрузок, изгиба корпуса, усталости и замеров толщин")
+	}
+	if mask&flagSensitivity != 0 {
+		fs.BoolVar(&o.sensitivity, "sensitivity", false, "посчитать чувствительность предельного момента и напряжений к параметрам связей")
+	}
+	fs.BoolVar(&o.quiet, "q", false, "печатать только ошибки")
+}
+
+// calcOptions параметры расчёта по флагам.
+func (o *options) calcOptions() *str.Options {
+	rez := str.Options{}
+	if o.verbose && !o.quiet {
+		rez.Observer = str.ObserverFunc(func(it *str.Iteration) {
+			fmt.Printf("Приближение %d: нейтральная ось %.6g м, момент инерции %.6g см2*м2, пластин %d\n",
+				it.ID, it.Rezult.CenterOfMass, it.Rezult.MomentOfInertia, len(it.Approx))
+		})
+	}
+	return &rez
+}
+
+// printf печатает сообщение, если не задан флаг -q.
+func (o *options) printf(format string, a ...interface{}) {
+	if !o.quiet {
+		fmt.Printf(format, a...)
+	}
+}
+
+// command команда командной строки.
+type command struct {
+	args  string // позиционные аргументы для справки
+	help  string // описание
+	flags int    // флаги команды
+	out   string // описание флага -o
+	run   func(args []string, opt *options) error
+}
+
+// commands команды по именам.
+var commands = map[string]*command{
+	"calc": {
+		args:  "файл",
+		help:  "посчитать книгу Excel или модель JSON/YAML",
+		flags: flagOutput | flagInPlace | flagLoadCase | flagAccuracy | flagVerbose | flagSensitivity,
+		out:   "файл результатов, по умолчанию rezult с расширением исходного файла",
+		run:   oneFile(calcFile),
+	},
+	"validate": {
+		args: "файл",
+		help: "проверить исходные данные без расчёта",
+		run:  oneFile(validateFile),
+	},
+	"template": {
+		args:  "файл",
+		help:  "создать книгу Excel для заполнения или пример модели JSON/YAML, формат по расширению",
+		flags: flagForce | flagFull,
+		run:   oneFile(templateFile),
+	},
+	"report": {
+		args:  "файл",
+		help:  "посчитать и записать приближения в таблицы CSV",
+		flags: flagOutput | flagLoadCase | flagAccuracy | flagVerbose,
+		out:   "каталог таблиц, по умолчанию текущий",
+		run:   oneFile(reportFile),
+	},
+	"sweep": {
+		args:  "файл",
+		help:  "посчитать сечение для ряда значений срока службы или расчётного момента",
+		flags: flagOutput | flagLoadCase | flagAccuracy | flagSweep,
+		out:   "таблица CSV, по умолчанию вывод на экран",
+		run:   sweepFile,
+	},
+	"convert": {
+		args:  "файл",
+		help:  "перевести исходные данные книги или модели в модель JSON/YAML",
+		flags: flagOutput | flagForce,
+		out:   "файл модели, формат по расширению (обязательно)",
+		run:   oneFile(convertFile),
+	},
+}
+
+// oneFile команда с одним файлом.
+func oneFile(run func(fileName string, opt *options) error) func(args []string, opt *options) error {
+	return func(args []string, opt *options) error {
+		if len(args) != 1 {
+			return usageError("необходимо одно имя файла")
+		}
+		return run(args[0], opt)
+	}
+}
+
+// flagSet набор флагов команды name.
+func (c *command) flagSet(name string, opt *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	opt.register(fs, c.flags, c.out)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "strength %s [флаги] %s\n  %s\n", name, c.args, c.help)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// usage печатает список команд.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "strength <команда> [флаги] файл")
+	fmt.Fprintln(w, "strength файл — то же что calc")
+	fmt.Fprintln(w, "Команды:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-9s %s\n", name, commands[name].help)
+	}
+	fmt.Fprintln(w, "Справка по флагам: strength <команда> -h")
+}
+
+// run выполняет команду по аргументам и возвращает код выхода.
+// Если первый аргумент не команда, выполняется calc.
+func run(args []string) int {
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return exitUsage
+	}
+	name := "calc"
+	if _, ok := commands[args[0]]; ok {
+		name, args = args[0], args[1:]
+	}
+	cmd := commands[name]
+	opt := options{}
+	fs := cmd.flagSet(name, &opt)
+	err := fs.Parse(args)
+	if err == flag.ErrHelp {
+		return exitOK
+	}
+	if err != nil {
+		return exitUsage
+	}
+	if opt.output != "" && opt.inPlace {
+		err = usageError("флаги -o и -i несовместимы")
+	} else {
+		err = cmd.run(fs.Args(), &opt)
+	}
+	if e, ok := err.(usageError); ok {
+		fmt.Fprintln(os.Stderr, e)
+		fs.Usage()
+		return exitUsage
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Что-то пошло не так")
+		printError(os.Stderr, err)
+		return exitFailure
+	}
+	opt.printf("Отработано\n")
+	return exitOK
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	str "github.com/kenits/strength"
+
+	excel "github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+const convergenceSheet = "Сходимость"
+
+// writeConvergence записывает изменения результата по приближениям.
+func writeConvergence(conv *str.Convergence, height []float64, file *excel.File) error {
+	file.NewSheet(convergenceSheet)
+	head := []interface{}{
+		"Приближение",
+		"Нейтральная ось",
+		"Смещение оси",
+		"Момент инерции",
+		"Изменение инерции %",
+		"Предельный момент",
+		"Изменение момента %",
+		"Критерий %",
+		"Изменились пластины",
+	}
+	for _, val := range height {
+		head = append(head, fmt.Sprintf("Изменение напряжения на %v м %%", val))
+	}
+	err := file.SetSheetRow(convergenceSheet, "A1", &head)
+	if err != nil {
+		return err
+	}
+	for key, step := range conv.Trace {
+		changed := make([]string, len(step.Changed))
+		for i, val := range step.Changed {
+			changed[i] = strconv.Itoa(val)
+		}
+		row := []interface{}{
+			step.Iteration,
+			step.CenterOfMass,
+			step.AxisResidual,
+			step.MomentOfInertia,
+			step.InertiaResidual,
+			step.Moment,
+			step.MomentResidual,
+			step.Criterion,
+			strings.Join(changed, ", "),
+		}
+		for _, val := range step.StrainResidual {
+			row = append(row, val)
+		}
+		err = file.SetSheetRow(convergenceSheet, fmt.Sprintf("A%d", key+2), &row)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printConvergence выводит краткие сведения о сходимости.
+func printConvergence(conv *str.Convergence, accuracy float64) {
+	state := "достигнута"
+	if !conv.Converged {
+		state = "не достигнута"
+	}
+	fmt.Printf("Сходимость (%v): приближений %d, точность %v %% %s\n", conv.Acceleration, conv.Iterations, accuracy, state)
+	if len(conv.Trace) == 0 {
+		return
+	}
+	last := conv.Trace[len(conv.Trace)-1]
+	fmt.Printf("Критерий последнего приближения %.4g %%, смещение оси %.4g м, изменение инерции %.4g %%\n",
+		last.Criterion, last.AxisResidual, last.InertiaResidual)
+	if len(last.Changed) != 0 {
+		fmt.Printf("В последнем приближении изменились пластины: %v\n", last.Changed)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+
+	str "github.com/kenits/strength"
+	"github.com/kenits/strength/model"
+
+	excel "github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+const deflectionSheet = "Изгиб корпуса"
+
+// readSupport читает условие закрепления из ячейки I1 листа изгиба.
+func readSupport(file *excel.File) (str.Support, error) {
+	val, err := file.GetCellValue(deflectionSheet, "I1")
+	if err != nil {
+		return 0, err
+	}
+	switch val {
+	case "", "по концам":
+		return str.SupportEnds, nil
+	case "заделка в корме":
+		return str.SupportAft, nil
+	case "заделка в носу":
+		return str.SupportFore, nil
+	}
+	return 0, &model.InputError{Sheet: deflectionSheet, Row: 1, Column: 9, Field: "support", Value: val,
+		Expected: `"по концам", "заделка в корме" or "заделка в носу"`}
+}
+
+// readStations читает станции: A абсцисса, B изгибающий момент, C момент инерции.
+// Если моменты инерции не заданы, они нулевые: по всей длине берётся момент инерции рассчитанного сечения.
+// Ошибки значений собираются в model.InputErrors.
+func readStations(file *excel.File) ([]str.Station, error) {
+	p := newSheetParser(file, deflectionSheet)
+	x, err := p.floats("A", 2, "x")
+	if err != nil {
+		return nil, err
+	}
+	moment, err := p.floats("B", 2, "moment")
+	if err != nil {
+		return nil, err
+	}
+	inertia, err := p.floats("C", 2, "moment_of_inertia")
+	if err != nil {
+		return nil, err
+	}
+	if len(moment) < len(x) {
+		p.fail(fmt.Sprintf("B%d", 2+len(moment)), "moment", "", "number")
+	}
+	if len(inertia) != 0 && len(inertia) < len(x) {
+		p.fail(fmt.Sprintf("C%d", 2+len(inertia)), "moment_of_inertia", "", "number")
+	}
+	if len(moment) > len(x) {
+		p.fail(fmt.Sprintf("A%d", 2+len(x)), "x", "", "number")
+	}
+	if err = p.err(); err != nil {
+		return nil, err
+	}
+
+	stations := make([]str.Station, len(x))
+	for key := range x {
+		stations[key] = str.Station{
+			X:      x[key],
+			Moment: moment[key],
+		}
+		if len(inertia) != 0 {
+			stations[key].MomentOfInertia = inertia[key]
+		}
+	}
+	return stations, nil
+}
+
+// writeDeflection записывает кривизну, углы поворота и прогибы рядом со станциями и строит график прогиба.
+func writeDeflection(data *str.Deflection, file *excel.File) error {
+	head := map[string]string{
+		"A1": "Абсцисса",
+		"B1": "Изгибающий момент",
+		"C1": "Момент инерции",
+		"D1": "Кривизна",
+		"E1": "Угол поворота",
+		"F1": "Прогиб",
+		"H1": "Опоры",
+	}
+	for addr, val := range head {
+		err := file.SetCellValue(deflectionSheet, addr, val)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := writeVerticalArrayFloat(deflectionSheet, "D", 2, data.Curvature, file)
+	if err != nil {
+		return err
+	}
+	_, err = writeVerticalArrayFloat(deflectionSheet, "E", 2, data.Slope, file)
+	if err != nil {
+		return err
+	}
+	_, err = writeVerticalArrayFloat(deflectionSheet, "F", 2, data.Deflection, file)
+	if err != nil {
+		return err
+	}
+
+	last := len(data.X) + 1
+	chart := fmt.Sprintf(`{"type":"line","series":[{"name":"'%[1]s'!$F$1","categories":"'%[1]s'!$A$2:$A$%[2]d","values":"'%[1]s'!$F$2:$F$%[2]d"}],"title":{"name":"Прогиб корпуса"},"legend":{"position":"bottom"}}`,
+		deflectionSheet, last)
+	return file.AddChart(deflectionSheet, "H3", chart)
+}
+
+// calcDeflection считает изгиб корпуса по станциям, для станций без момента инерции
+// берётся момент инерции рассчитанного сечения.
+func calcDeflection(basedata *str.BaseData, rezult *str.Rezult, support str.Support, stations []str.Station, file *excel.File) error {
+	for key := range stations {
+		if stations[key].MomentOfInertia == 0 {
+			stations[key].MomentOfInertia = rezult.MomentOfInertia
+		}
+	}
+	data, err := str.CalcDeflection(stations, basedata.ElasticModul, support)
+	if err != nil {
+		return err
+	}
+	return writeDeflection(data, file)
+}
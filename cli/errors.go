@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kenits/strength/model"
+
+	excel "github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+// sheetParser разбирает значения ячеек листа.
+// Ошибки значений собираются, а не прерывают чтение, чтобы показать все ошибки листа разом.
+type sheetParser struct {
+	file  *excel.File
+	sheet string
+	errs  model.InputErrors
+}
+
+// newSheetParser создаёт разборщик листа sheet.
+func newSheetParser(file *excel.File, sheet string) *sheetParser {
+	return &sheetParser{file: file, sheet: sheet}
+}
+
+// fail добавляет ошибку значения ячейки addr.
+func (p *sheetParser) fail(addr, field, value, expected string) {
+	e := model.InputError{Sheet: p.sheet, Field: field, Value: value, Expected: expected}
+	col, row, err := excel.CellNameToCoordinates(addr)
+	if err == nil {
+		e.Row, e.Column = row, col
+	}
+	p.errs = append(p.errs, e)
+}
+
+// float разбирает число val ячейки addr, пустое значение def.
+func (p *sheetParser) float(addr, field, val string, def float64) float64 {
+	if strings.TrimSpace(val) == "" {
+		return def
+	}
+	rez, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+	if err != nil {
+		p.fail(addr, field, val, "number")
+		return def
+	}
+	return rez
+}
+
+// int разбирает целое val ячейки addr.
+func (p *sheetParser) int(addr, field, val string) int {
+	rez, err := strconv.Atoi(strings.TrimSpace(val))
+	if err != nil {
+		p.fail(addr, field, val, "integer")
+	}
+	return rez
+}
+
+// cell значение ячейки addr.
+func (p *sheetParser) cell(addr string) (string, error) {
+	return p.file.GetCellValue(p.sheet, addr)
+}
+
+// floats читает числа столбца column начиная со строки row до первой пустой ячейки.
+// Возвращается ошибка чтения книги, ошибки значений собираются.
+func (p *sheetParser) floats(column string, row int, field string) ([]float64, error) {
+	data, err := readVerticalArray(p.sheet, column, row, p.file)
+	if err != nil {
+		return nil, err
+	}
+	rez := make([]float64, len(data))
+	for key, val := range data {
+		rez[key] = p.float(fmt.Sprintf("%s%d", column, row+key), field, val, 0)
+	}
+	return rez, nil
+}
+
+// ints читает целые столбца column начиная со строки row до первой пустой ячейки.
+func (p *sheetParser) ints(column string, row int, field string) ([]int, error) {
+	data, err := readVerticalArray(p.sheet, column, row, p.file)
+	if err != nil {
+		return nil, err
+	}
+	rez := make([]int, len(data))
+	for key, val := range data {
+		rez[key] = p.int(fmt.Sprintf("%s%d", column, row+key), field, val)
+	}
+	return rez, nil
+}
+
+// err ошибки значений листа, nil если их нет.
+func (p *sheetParser) err() error {
+	return p.errs.Err()
+}
+
+// printError печатает ошибку в w, ошибки исходных данных по одной на строке.
+func printError(w io.Writer, err error) {
+	errs, ok := err.(model.InputErrors)
+	if !ok {
+		fmt.Fprintln(w, err)
+		return
+	}
+	fmt.Fprintf(w, "Ошибки в исходных данных: %d\n", len(errs))
+	for key := range errs {
+		fmt.Fprintln(w, errs[key].Error())
+	}
+}
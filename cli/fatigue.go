@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	str "github.com/kenits/strength"
+
+	excel "github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+const fatigueSheet = "Усталость"
+
+// fatigueStartRow первая строка таблицы узлов.
+const fatigueStartRow = 7
+
+// connectionTypes типы соединений по названиям в книге.
+var connectionTypes = map[string]str.ConnectionType{
+	"кницы с двух сторон":   str.ConnectionBracketBoth,
+	"кница с одной стороны": str.ConnectionBracketOne,
+	"ребро на стенке":       str.ConnectionWebStiffener,
+	"без книц":              str.ConnectionPlain,
+}
+
+// readFatigueLoad читает нагрузки: B1 размах момента, B2 параметр формы, B3 число циклов, B4 срок службы.
+func readFatigueLoad(file *excel.File) (*str.FatigueLoad, error) {
+	p := newSheetParser(file, fatigueSheet)
+	addr := [4]string{"B1", "B2", "B3", "B4"}
+	fields := [4]string{"moment_range", "shape", "cycles", "design_life"}
+	vals := [4]float64{}
+	for key, val := range addr {
+		data, err := p.cell(val)
+		if err != nil {
+			return nil, err
+		}
+		if data == "" {
+			p.fail(val, fields[key], data, "number")
+			continue
+		}
+		vals[key] = p.float(val, fields[key], data, 0)
+	}
+	if err := p.err(); err != nil {
+		return nil, err
+	}
+	load := str.FatigueLoad{
+		MomentRange: vals[0],
+		Shape:       vals[1],
+		Cycles:      vals[2],
+		DesignLife:  vals[3],
+	}
+	return &load, nil
+}
+
+// readFatigueDetails читает узлы с 7 строки: A № жёсткой связи, B шпация, C пролёт,
+// D момент сопротивления, E размах давления, F тип соединения, G коэффициент концентрации, H кривая усталости.
+// Ошибки значений собираются в model.InputErrors.
+func readFatigueDetails(file *excel.File) (map[int]str.FatigueDetail, error) {
+	p := newSheetParser(file, fatigueSheet)
+	id, err := p.ints("A", fatigueStartRow, "id")
+	if err != nil {
+		return nil, err
+	}
+	columns := [4]string{"B", "C", "D", "E"}
+	fields := [4]string{"spacing", "span", "section_modulus", "pressure_range"}
+	vals := [4][]float64{}
+	for key, column := range columns {
+		vals[key], err = p.floats(column, fatigueStartRow, fields[key])
+		if err != nil {
+			return nil, err
+		}
+		if len(vals[key]) < len(id) {
+			p.fail(fmt.Sprintf("%s%d", column, fatigueStartRow+len(vals[key])), fields[key], "", "number")
+		}
+	}
+	if err = p.err(); err != nil {
+		return nil, err
+	}
+
+	rez := make(map[int]str.FatigueDetail, len(id))
+	for key := range id {
+		row := fatigueStartRow + key
+		connection, err := p.cell(fmt.Sprintf("F%d", row))
+		if err != nil {
+			return nil, err
+		}
+		scf, err := p.cell(fmt.Sprintf("G%d", row))
+		if err != nil {
+			return nil, err
+		}
+		curve, err := p.cell(fmt.Sprintf("H%d", row))
+		if err != nil {
+			return nil, err
+		}
+
+		d := str.FatigueDetail{
+			Spacing:        vals[0][key],
+			Span:           vals[1][key],
+			SectionModulus: vals[2][key],
+			PressureRange:  vals[3][key],
+			SCF:            p.float(fmt.Sprintf("G%d", row), "scf", scf, 0),
+		}
+		var ok bool
+		d.Connection, ok = connectionTypes[connection]
+		if !ok {
+			p.fail(fmt.Sprintf("F%d", row), "connection", connection, `"кницы с двух сторон", "кница с одной стороны", "ребро на стенке" or "без книц"`)
+		}
+		d.Curve, ok = str.SNCurves[curve]
+		if !ok {
+			p.fail(fmt.Sprintf("H%d", row), "curve", curve, "S-N curve")
+		}
+		rez[id[key]] = d
+	}
+	if err = p.err(); err != nil {
+		return nil, err
+	}
+	return rez, nil
+}
+
+// unlimitedLife отметка долговечности узла без усталостных повреждений.
+const unlimitedLife = "не ограничена"
+
+// writeFatigue записывает результаты в столбцы I..M рядом с узлами.
+func writeFatigue(data map[int]str.Fatigue, file *excel.File) error {
+	head := []string{
+		"Размах от общего изгиба",
+		"Размах от давления",
+		"Расчётный размах",
+		"Повреждённость",
+		"Долговечность",
+	}
+	err := file.SetSheetRow(fatigueSheet, fmt.Sprintf("I%d", fatigueStartRow-1), &head)
+	if err != nil {
+		return err
+	}
+	id, err := readVerticalArrayInt(fatigueSheet, "A", fatigueStartRow, file)
+	if err != nil {
+		return err
+	}
+	for key, val := range id {
+		f := data[val]
+		var life interface{} = f.Life
+		// без повреждений долговечность бесконечна, в ячейку её не записать
+		if math.IsInf(f.Life, 1) {
+			life = unlimitedLife
+		}
+		row := []interface{}{
+			f.GlobalRange,
+			f.LocalRange,
+			f.StressRange,
+			f.Damage,
+			life,
+		}
+		err = file.SetSheetRow(fatigueSheet, fmt.Sprintf("I%d", fatigueStartRow+key), &row)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// calcFatigue считает усталость узлов по прочитанным нагрузкам и узлам.
+func calcFatigue(rezult *str.Rezult, rigid map[int]str.Rigid, load *str.FatigueLoad, details map[int]str.FatigueDetail, file *excel.File) error {
+	data, err := str.CalcFatigue(rezult, rigid, load, details)
+	if err != nil {
+		return err
+	}
+	return writeFatigue(data, file)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	str "github.com/kenits/strength"
+
+	excel "github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+func Test_writeFatigue(t *testing.T) {
+	file := excel.NewFile()
+	file.NewSheet(fatigueSheet)
+	for key, id := range []int{1, 2} {
+		file.SetCellValue(fatigueSheet, cellName(1, fatigueStartRow+key), id)
+	}
+	data := map[int]str.Fatigue{
+		1: {ID: 1, StressRange: 100, Damage: 0.5, Life: 40},
+		2: {ID: 2, Life: math.Inf(1)},
+	}
+	if err := writeFatigue(data, file); err != nil {
+		t.Fatalf("writeFatigue() error = %v", err)
+	}
+	for key, want := range []string{"40", unlimitedLife} {
+		cell := cellName(13, fatigueStartRow+key)
+		got, err := file.GetCellValue(fatigueSheet, cell)
+		if err != nil {
+			t.Fatalf("GetCellValue(%s) error = %v", cell, err)
+		}
+		if got != want {
+			t.Errorf("writeFatigue() %s = %q, want %q", cell, got, want)
+		}
+	}
+}
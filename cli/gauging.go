@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+
+	str "github.com/kenits/strength"
+
+	excel "github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+const gaugingSheet = "Замеры толщин"
+
+// gaugingStartRow первая строка таблицы замеров.
+const gaugingStartRow = 4
+
+// readGauging читает лист замеров.
+// B1 допускаемый износ %, B2 "минимальная" если считать по минимальной толщине.
+// Таблица с 4 строки: тип связи (жёсткая/гибкая), номер, построечная толщина, замеры толщин.
+// Ошибки значений собираются в model.InputErrors.
+func readGauging(file *excel.File) (*str.GaugingData, error) {
+	p := newSheetParser(file, gaugingSheet)
+	allowableStr, err := p.cell("B1")
+	if err != nil {
+		return nil, err
+	}
+	mode, err := p.cell("B2")
+	if err != nil {
+		return nil, err
+	}
+
+	data := str.GaugingData{
+		UseMin:    mode == "минимальная",
+		Allowable: p.float("B1", "allowable", allowableStr, 0),
+		Rigid:     make(map[int]str.Gauging),
+		Flex:      make(map[int]str.Gauging),
+	}
+
+	rows, err := file.GetRows(gaugingSheet)
+	if err != nil {
+		return nil, err
+	}
+	for i := gaugingStartRow - 1; i < len(rows); i++ {
+		row := rows[i]
+		if len(row) < 2 || row[1] == "" {
+			continue
+		}
+		g := str.Gauging{ID: p.int(fmt.Sprintf("B%d", i+1), "id", row[1])}
+		if len(row) > 2 {
+			g.ThicknessStart = p.float(fmt.Sprintf("C%d", i+1), "thickness", row[2], 0)
+		}
+		for j := 3; j < len(row); j++ {
+			if row[j] == "" {
+				continue
+			}
+			addr, err := excel.CoordinatesToCellName(j+1, i+1)
+			if err != nil {
+				return nil, err
+			}
+			g.Readings = append(g.Readings, p.float(addr, "reading", row[j], 0))
+		}
+
+		switch row[0] {
+		case "жёсткая":
+			data.Rigid[g.ID] = g
+		case "гибкая":
+			data.Flex[g.ID] = g
+		default:
+			p.fail(fmt.Sprintf("A%d", i+1), "type", row[0], `"жёсткая" or "гибкая"`)
+		}
+	}
+	if err = p.err(); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// writeGauging записывает результаты дефектации на отдельный лист.
+func writeGauging(data *str.GaugingData, file *excel.File) error {
+	sheetName := "Износ"
+	file.NewSheet(sheetName)
+	head := []string{
+		"Тип связи",
+		"№",
+		"Построечная толщина",
+		"Средняя толщина",
+		"Минимальная толщина",
+		"Износ %",
+		"Превышение допускаемого",
+	}
+	err := file.SetSheetRow(sheetName, "A1", &head)
+	if err != nil {
+		return err
+	}
+	row := 2
+	for _, id := range str.GaugingIDs(data.Rigid) {
+		val := data.Rigid[id]
+		err = writeGaugingRow(sheetName, row, "жёсткая", &val, file)
+		if err != nil {
+			return err
+		}
+		row++
+	}
+	for _, id := range str.GaugingIDs(data.Flex) {
+		val := data.Flex[id]
+		err = writeGaugingRow(sheetName, row, "гибкая", &val, file)
+		if err != nil {
+			return err
+		}
+		row++
+	}
+	return nil
+}
+
+func writeGaugingRow(sheetName string, row int, kind string, g *str.Gauging, file *excel.File) error {
+	excess := "нет"
+	if g.Excess {
+		excess = "да"
+	}
+	vals := []interface{}{
+		kind,
+		g.ID,
+		g.ThicknessStart,
+		g.Mean,
+		g.Min,
+		g.Diminution,
+		excess,
+	}
+	return file.SetSheetRow(sheetName, fmt.Sprintf("A%d", row), &vals)
+}
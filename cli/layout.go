@@ -0,0 +1,435 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kenits/strength/model"
+
+	excel "github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+// headerSearchRows сколько первых строк листа просматривается в поисках заголовка таблицы.
+const headerSearchRows = 50
+
+// cellRange прямоугольная область листа, номера строк и столбцов с 1.
+type cellRange struct {
+	sheet      string
+	col1, row1 int
+	col2, row2 int
+}
+
+// region таблица на листе: заголовок в первой строке, данные до первой пустой строки.
+type region struct {
+	sheet   string
+	row     int        // строка заголовка
+	col     int        // первый столбец
+	records [][]string // заголовок и строки данных начиная со столбца col
+}
+
+// lastCol последний непустой столбец заголовка.
+func (r *region) lastCol() int {
+	rez := r.col
+	for key, val := range r.records[0] {
+		if strings.TrimSpace(val) != "" {
+			rez = r.col + key
+		}
+	}
+	return rez
+}
+
+// xmlWorkbook листы и имена книги.
+type xmlWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+	DefinedNames []struct {
+		Name string `xml:"name,attr"`
+		Ref  string `xml:",chardata"`
+	} `xml:"definedNames>definedName"`
+}
+
+// xmlRels связи части книги.
+type xmlRels struct {
+	Rels []struct {
+		ID     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xmlTable таблица Excel.
+type xmlTable struct {
+	Name        string `xml:"name,attr"`
+	DisplayName string `xml:"displayName,attr"`
+	Ref         string `xml:"ref,attr"`
+}
+
+// relsPath путь файла связей части книги.
+func relsPath(part string) string {
+	dir, name := path.Split(part)
+	return dir + "_rels/" + name + ".rels"
+}
+
+// readRels читает связи части книги, nil если их нет.
+func readRels(file *excel.File, part string) (*xmlRels, error) {
+	data, ok := file.XLSX[relsPath(part)]
+	if !ok {
+		return nil, nil
+	}
+	var rels xmlRels
+	err := xml.Unmarshal(data, &rels)
+	if err != nil {
+		return nil, err
+	}
+	return &rels, nil
+}
+
+// resolvePart путь части книги по ссылке target из части from.
+func resolvePart(from, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(from), target)
+}
+
+// parseRange разбирает ссылку на область вида A1:F10 или $A$1.
+func parseRange(sheet, ref string) (cellRange, error) {
+	ref = strings.Replace(ref, "$", "", -1)
+	parts := strings.Split(ref, ":")
+	if len(parts) > 2 {
+		return cellRange{}, fmt.Errorf("bad range %q", ref)
+	}
+	rez := cellRange{sheet: sheet}
+	var err error
+	rez.col1, rez.row1, err = excel.CellNameToCoordinates(parts[0])
+	if err != nil {
+		return cellRange{}, err
+	}
+	rez.col2, rez.row2 = rez.col1, rez.row1
+	if len(parts) == 2 {
+		rez.col2, rez.row2, err = excel.CellNameToCoordinates(parts[1])
+		if err != nil {
+			return cellRange{}, err
+		}
+	}
+	return rez, nil
+}
+
+// namedRanges именованные области и таблицы Excel книги по именам.
+// Имена с формулами и несколькими областями пропускаются.
+func namedRanges(file *excel.File) (map[string]cellRange, error) {
+	rez := make(map[string]cellRange)
+	const workbook = "xl/workbook.xml"
+	var wb xmlWorkbook
+	err := xml.Unmarshal(file.XLSX[workbook], &wb)
+	if err != nil {
+		return nil, err
+	}
+	for _, val := range wb.DefinedNames {
+		ref := strings.TrimSpace(val.Ref)
+		pos := strings.LastIndex(ref, "!")
+		if pos < 0 || strings.ContainsAny(ref, ",()") {
+			continue
+		}
+		sheet := strings.Trim(ref[:pos], "'")
+		sheet = strings.Replace(sheet, "''", "'", -1)
+		r, err := parseRange(sheet, ref[pos+1:])
+		if err != nil {
+			continue
+		}
+		rez[val.Name] = r
+	}
+
+	rels, err := readRels(file, workbook)
+	if err != nil || rels == nil {
+		return rez, err
+	}
+	for _, sheet := range wb.Sheets {
+		for _, rel := range rels.Rels {
+			if rel.ID != sheet.ID {
+				continue
+			}
+			part := resolvePart(workbook, rel.Target)
+			sheetRels, err := readRels(file, part)
+			if err != nil {
+				return nil, err
+			}
+			if sheetRels == nil {
+				continue
+			}
+			for _, t := range sheetRels.Rels {
+				if !strings.HasSuffix(t.Type, "/table") {
+					continue
+				}
+				var table xmlTable
+				err = xml.Unmarshal(file.XLSX[resolvePart(part, t.Target)], &table)
+				if err != nil {
+					return nil, err
+				}
+				r, err := parseRange(sheet.Name, table.Ref)
+				if err != nil {
+					return nil, fmt.Errorf("table %q: %v", table.DisplayName, err)
+				}
+				rez[table.DisplayName] = r
+				rez[table.Name] = r
+			}
+		}
+	}
+	return rez, nil
+}
+
+// rangeRecords значения ячеек области по строкам.
+func rangeRecords(file *excel.File, r cellRange) ([][]string, error) {
+	rows, err := file.GetRows(r.sheet)
+	if err != nil {
+		return nil, err
+	}
+	rez := make([][]string, 0, r.row2-r.row1+1)
+	for row := r.row1; row <= r.row2; row++ {
+		record := make([]string, r.col2-r.col1+1)
+		if row <= len(rows) {
+			for col := r.col1; col <= r.col2 && col <= len(rows[row-1]); col++ {
+				record[col-r.col1] = rows[row-1][col-1]
+			}
+		}
+		rez = append(rez, record)
+	}
+	return rez, nil
+}
+
+// findTable находит таблицу: сначала таблицу Excel или именованную область с одним из имён names,
+// затем на листе sheet строку заголовка со всеми обязательными столбцами.
+// Если заголовок не найден, ошибки model.InputErrors называют недостающие столбцы строки,
+// больше всего похожей на заголовок.
+func findTable(file *excel.File, sheet string, names *model.Column, columns []model.Column) (*region, error) {
+	ranges, err := namedRanges(file)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(ranges))
+	for name := range ranges {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	for _, name := range keys {
+		r := ranges[name]
+		if !names.Match(name) {
+			continue
+		}
+		records, err := rangeRecords(file, r)
+		if err != nil {
+			return nil, err
+		}
+		_, err = model.ParseHeader(records[0], columns)
+		if errs, ok := err.(model.InputErrors); ok {
+			errs.Locate(r.sheet, r.row1, r.col1)
+			return nil, errs
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &region{sheet: r.sheet, row: r.row1, col: r.col1, records: records}, nil
+	}
+
+	if file.GetSheetIndex(sheet) == 0 {
+		return nil, model.InputErrors{{Sheet: sheet, Expected: fmt.Sprintf("sheet or table %q", names.Name)}}
+	}
+	rows, err := file.GetRows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		headerErr model.InputErrors
+		best      int
+	)
+	for key := 0; key < len(rows) && key < headerSearchRows; key++ {
+		if empty(rows[key]) {
+			continue
+		}
+		h, err := model.ParseHeader(rows[key], columns)
+		if errs, ok := err.(model.InputErrors); ok {
+			if n := matched(rows[key], columns); headerErr == nil || n > best {
+				errs.Locate(sheet, key+1, 1)
+				headerErr, best = errs, n
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		records := [][]string{rows[key]}
+		for _, record := range rows[key+1:] {
+			if emptyColumns(record, h) {
+				break
+			}
+			records = append(records, record)
+		}
+		return &region{sheet: sheet, row: key + 1, col: 1, records: records}, nil
+	}
+	if headerErr == nil {
+		headerErr = model.InputErrors{{Sheet: sheet, Expected: "header"}}
+	}
+	return nil, headerErr
+}
+
+// matched количество столбцов найденных в строке.
+func matched(record []string, columns []model.Column) int {
+	rez := 0
+	for _, val := range record {
+		for key := range columns {
+			if columns[key].Match(val) {
+				rez++
+				break
+			}
+		}
+	}
+	return rez
+}
+
+// empty true если в строке нет значений.
+func empty(record []string) bool {
+	for _, val := range record {
+		if strings.TrimSpace(val) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// emptyColumns true если в строке пусты все столбцы заголовка.
+func emptyColumns(record []string, h *model.Header) bool {
+	for _, key := range h.Index {
+		if key < len(record) && strings.TrimSpace(record[key]) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// param значение параметра и адрес его ячейки.
+type param struct {
+	value string
+	cell  string
+}
+
+// findParams находит на листе значения параметров по подписям: значение в ячейке справа от подписи.
+// Возвращает значения по именам параметров, ошибки model.InputErrors если нет обязательных подписей.
+// Если на листе нет ни одной подписи, возвращает nil.
+func findParams(file *excel.File, sheet string, params []model.Column) (map[string]param, error) {
+	rows, err := file.GetRows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	rez := make(map[string]param)
+	for row, record := range rows {
+		for col, val := range record {
+			for key := range params {
+				p := &params[key]
+				if _, ok := rez[p.Name]; ok || !p.Match(val) {
+					continue
+				}
+				cell, err := excel.CoordinatesToCellName(col+2, row+1)
+				if err != nil {
+					return nil, err
+				}
+				v := param{cell: cell}
+				if col+1 < len(record) {
+					v.value = strings.TrimSpace(record[col+1])
+				}
+				rez[p.Name] = v
+				break
+			}
+		}
+	}
+	if len(rez) == 0 {
+		return nil, nil
+	}
+	var errs model.InputErrors
+	for key := range params {
+		if _, ok := rez[params[key].Name]; params[key].Required && !ok {
+			errs = append(errs, params[key].Missing(sheet, "label"))
+		}
+	}
+	return rez, errs.Err()
+}
+
+// elementTable таблица связей на листе, результаты пишутся в столбцы справа от исходных данных.
+type elementTable struct {
+	sheet string
+	row   int         // строка заголовка
+	col   int         // первый столбец результатов
+	rows  map[int]int // строки по номерам связей
+}
+
+// newElementTable находит строки связей таблицы и столбец результатов за последним столбцом исходных данных.
+func newElementTable(reg *region, columns []model.Column) (*elementTable, error) {
+	h, err := model.ParseHeader(reg.records[0], columns)
+	if err != nil {
+		return nil, err
+	}
+	rez := elementTable{
+		sheet: reg.sheet,
+		row:   reg.row,
+		rows:  make(map[int]int, len(reg.records)),
+	}
+	last := 0
+	for _, key := range h.Index {
+		if key > last {
+			last = key
+		}
+	}
+	rez.col = reg.col + last + 1
+	id := h.Index["id"]
+	for key, record := range reg.records[1:] {
+		if id >= len(record) {
+			continue
+		}
+		val, err := strconv.Atoi(strings.TrimSpace(record[id]))
+		if err != nil {
+			continue
+		}
+		rez.rows[val] = reg.row + key + 1
+	}
+	return &rez, nil
+}
+
+// writeHead подписывает столбцы результатов, если в заголовке они пусты.
+func (t *elementTable) writeHead(names []string, file *excel.File) error {
+	for key, val := range names {
+		addr, err := excel.CoordinatesToCellName(t.col+key, t.row)
+		if err != nil {
+			return err
+		}
+		data, err := file.GetCellValue(t.sheet, addr)
+		if err != nil {
+			return err
+		}
+		if data != "" {
+			continue
+		}
+		err = file.SetCellValue(t.sheet, addr, val)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRow пишет результаты связи id в её строку.
+func (t *elementTable) writeRow(id int, vals []interface{}, file *excel.File) error {
+	row, ok := t.rows[id]
+	if !ok {
+		return fmt.Errorf("%s: missing row of %d", t.sheet, id)
+	}
+	addr, err := excel.CoordinatesToCellName(t.col, row)
+	if err != nil {
+		return err
+	}
+	return file.SetSheetRow(t.sheet, addr, &vals)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/kenits/strength/model"
+)
+
+// calcModel считает модель из JSON или YAML и пишет результаты в том же формате
+// или в формате файла -o. Если в модели есть случаи загрузки, сечение считается по каждому
+// из них или только по случаю -case.
+func calcModel(fileName string, opt *options) error {
+	if opt.inPlace {
+		return usageError("результаты модели пишутся в отдельный файл, флаг -i только для книг Excel")
+	}
+	output := opt.output
+	if output == "" {
+		output = "rezult" + filepath.Ext(fileName)
+	}
+	if _, ok := model.FormatOf(output); !ok {
+		return usageError("формат результатов по расширению: .json, .yaml или .yml")
+	}
+	p, err := readProject(fileName)
+	if err != nil {
+		return err
+	}
+	cases, err := p.cases(opt.loadCase)
+	if err != nil {
+		return err
+	}
+	rez := model.NewRezult(p.baseData)
+	for _, loadCase := range cases {
+		rezult, conv, err := p.calculate(nil, loadCase, opt)
+		if err != nil {
+			return err
+		}
+		name := ""
+		if loadCase != nil {
+			name = loadCase.Name
+		}
+		rez.Add(name, rezult, &conv)
+	}
+	return model.WriteFile(output, rez)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+
+	str "github.com/kenits/strength"
+
+	excel "github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+const pressureSheet = "Нагрузки"
+
+// pressureStartRow первая строка таблицы пластин.
+const pressureStartRow = 8
+
+// readLoadCase читает лист нагрузок.
+// B1 осадка, B2 плотность забортной воды (пусто 1.025), волновая нагрузка если задана B3:
+// B3 длина судна, B4 скорость хода, B5 коэффициент распределения по длине.
+// Таблица с 8 строки: номер гибкой связи, "да" для наружной обшивки, уровень налива,
+// плотность груза, избыточное давление в цистерне. Ошибки значений собираются в model.InputErrors.
+func readLoadCase(file *excel.File) (*str.LoadCase, error) {
+	p := newSheetParser(file, pressureSheet)
+	addr := [5]string{"B1", "B2", "B3", "B4", "B5"}
+	fields := [5]string{"draft", "sea_density", "length", "speed", "distribution"}
+	vals := [5]float64{}
+	for key, val := range addr {
+		data, err := p.cell(val)
+		if err != nil {
+			return nil, err
+		}
+		vals[key] = p.float(val, fields[key], data, 0)
+	}
+	lc := str.LoadCase{
+		Name:       pressureSheet,
+		Draft:      vals[0],
+		SeaDensity: vals[1],
+		Tanks:      make(map[string]str.Tank),
+		Exposure:   make(map[int]str.Exposure),
+	}
+	if vals[2] != 0 {
+		lc.Wave = &str.WaveLoad{Length: vals[2], Speed: vals[3], Distribution: vals[4]}
+	}
+
+	rows, err := file.GetRows(pressureSheet)
+	if err != nil {
+		return nil, err
+	}
+	tankFields := [3]string{"filling", "density", "overpressure"}
+	for i := pressureStartRow - 1; i < len(rows); i++ {
+		row := rows[i]
+		if len(row) < 1 || row[0] == "" {
+			continue
+		}
+		id := p.int(fmt.Sprintf("A%d", i+1), "id", row[0])
+		e := str.Exposure{Sea: len(row) > 1 && row[1] == "да"}
+		tank := [3]float64{}
+		filled := false
+		for j := range tank {
+			if len(row) <= j+2 || row[j+2] == "" {
+				continue
+			}
+			addr, err := excel.CoordinatesToCellName(j+3, i+1)
+			if err != nil {
+				return nil, err
+			}
+			tank[j] = p.float(addr, tankFields[j], row[j+2], 0)
+			filled = true
+		}
+		if filled {
+			e.Tank = row[0]
+			lc.Tanks[e.Tank] = str.Tank{Name: e.Tank, Filling: tank[0], Density: tank[1], Overpressure: tank[2]}
+		}
+		lc.Exposure[id] = e
+	}
+	if err = p.err(); err != nil {
+		return nil, err
+	}
+	return &lc, nil
+}
+
+// writePressure записывает назначенные давления в столбец F.
+func writePressure(flex map[int]str.Flex, file *excel.File) error {
+	err := file.SetCellValue(pressureSheet, fmt.Sprintf("F%d", pressureStartRow-1), "Давление")
+	if err != nil {
+		return err
+	}
+	id, err := readVerticalArrayInt(pressureSheet, "A", pressureStartRow, file)
+	if err != nil {
+		return err
+	}
+	for key, val := range id {
+		err = file.SetCellValue(pressureSheet, fmt.Sprintf("F%d", pressureStartRow+key), flex[val].Pressure)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	str "github.com/kenits/strength"
+	"github.com/kenits/strength/model"
+
+	excel "github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+// project исходные данные расчёта из книги Excel или модели JSON/YAML.
+type project struct {
+	baseData  *str.BaseData
+	rigid     map[int]str.Rigid
+	flex      map[int]str.Flex
+	loadCases []str.LoadCase
+	gauging   *str.GaugingData // замеры толщин книги, nil если их нет
+}
+
+// readProject читает исходные данные, формат по расширению файла.
+func readProject(fileName string) (*project, error) {
+	if _, ok := model.FormatOf(fileName); ok {
+		doc, err := model.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		p := project{baseData: doc.BaseData()}
+		p.rigid, p.flex, err = doc.Elements()
+		if err != nil {
+			return nil, err
+		}
+		for key := range doc.LoadCases {
+			lc, err := doc.LoadCases[key].LoadCase()
+			if err != nil {
+				return nil, err
+			}
+			p.loadCases = append(p.loadCases, *lc)
+		}
+		return &p, nil
+	}
+
+	file, err := excel.OpenFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	in, err := readInput(file)
+	if err != nil {
+		return nil, err
+	}
+	p := project{baseData: in.baseData, rigid: in.rigid, flex: in.flex, gauging: in.gauging}
+	if in.loadCase != nil {
+		p.loadCases = []str.LoadCase{*in.loadCase}
+	}
+	return &p, nil
+}
+
+// cases случаи загрузки для расчёта: с именем name или все, если имя пусто.
+// Без случаев загрузки возвращается один nil: расчёт по давлениям связей.
+func (p *project) cases(name string) ([]*str.LoadCase, error) {
+	if len(p.loadCases) == 0 {
+		if name != "" {
+			return nil, fmt.Errorf("missing load case %q", name)
+		}
+		return []*str.LoadCase{nil}, nil
+	}
+	var rez []*str.LoadCase
+	for key := range p.loadCases {
+		if name == "" || p.loadCases[key].Name == name {
+			rez = append(rez, &p.loadCases[key])
+		}
+	}
+	if len(rez) == 0 {
+		return nil, fmt.Errorf("missing load case %q", name)
+	}
+	return rez, nil
+}
+
+// calculate считает сечение по случаю загрузки loadCase (nil без нагрузок) на копиях связей.
+// Исходные данные base заменяют исходные данные проекта, если не nil.
+func (p *project) calculate(base *str.BaseData, loadCase *str.LoadCase, opt *options) (str.Iterations, str.Convergence, error) {
+	baseData := *p.baseData
+	if base != nil {
+		baseData = *base
+	}
+	if opt.accuracy != 0 {
+		baseData.Accuracy = opt.accuracy
+	}
+	rigid := make(map[int]str.Rigid, len(p.rigid))
+	for id, val := range p.rigid {
+		rigid[id] = val
+	}
+	flex := make(map[int]str.Flex, len(p.flex))
+	for id, val := range p.flex {
+		flex[id] = val
+	}
+	str.CalcAllRigid(rigid, baseData.Age)
+	str.CalcAllFlex(flex, baseData.Age)
+	if loadCase != nil {
+		err := str.ApplyPressure(loadCase, flex)
+		if err != nil {
+			return nil, str.Convergence{}, err
+		}
+	}
+	if p.gauging != nil {
+		err := str.ApplyGauging(p.gauging, rigid, flex)
+		if err != nil {
+			return nil, str.Convergence{}, err
+		}
+	}
+	rezult, conv, err := str.CalculateWithOptions(&baseData, rigid, flex, opt.calcOptions())
+	if err != nil {
+		return nil, conv, err
+	}
+	if !opt.quiet {
+		printConvergence(&conv, baseData.Accuracy)
+	}
+	return rezult, conv, nil
+}
+
+// validateFile проверяет исходные данные без расчёта.
+func validateFile(fileName string, opt *options) error {
+	p, err := readProject(fileName)
+	if err != nil {
+		return err
+	}
+	opt.printf("Исходные данные в порядке: расчётных точек %d, жёстких связей %d, гибких связей %d, случаев загрузки %d\n",
+		len(p.baseData.Height), len(p.rigid), len(p.flex), len(p.loadCases))
+	return nil
+}
+
+// convertFile переводит исходные данные в модель JSON или YAML файла -o.
+// Замеры толщин, изгиб корпуса и усталость книги в модель не входят.
+func convertFile(fileName string, opt *options) error {
+	if opt.output == "" {
+		return usageError("необходим файл модели -o")
+	}
+	if _, ok := model.FormatOf(opt.output); !ok {
+		return usageError("формат модели по расширению: .json, .yaml или .yml")
+	}
+	err := checkOverwrite(opt.output, opt)
+	if err != nil {
+		return err
+	}
+	p, err := readProject(fileName)
+	if err != nil {
+		return err
+	}
+	if p.gauging != nil {
+		opt.printf("Замеры толщин в модель не входят\n")
+	}
+	return model.WriteFile(opt.output, model.New(p.baseData, p.rigid, p.flex, p.loadCases))
+}
+
+// checkOverwrite ошибка если файл существует, а флаг -f не задан.
+func checkOverwrite(fileName string, opt *options) error {
+	_, err := os.Stat(fileName)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !opt.force {
+		return fmt.Errorf("file %s exists, use -f to overwrite", fileName)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	str "github.com/kenits/strength"
+	"github.com/kenits/strength/model"
+)
+
+// reportTables таблицы приближений отчёта по именам файлов.
+var reportTables = []struct {
+	name  string
+	write func(w io.Writer, data str.Iterations) error
+}{
+	{"sections.csv", model.WriteSectionsCSV},
+	{"points.csv", model.WritePointsCSV},
+	{"plates.csv", model.WritePlatesCSV},
+}
+
+// reportFile считает сечение и пишет приближения каждого случая загрузки в таблицы CSV каталога -o.
+// Таблицы случая загрузки называются по имени случая: "шторм_sections.csv".
+func reportFile(fileName string, opt *options) error {
+	dir := opt.output
+	if dir == "" {
+		dir = "."
+	}
+	p, err := readProject(fileName)
+	if err != nil {
+		return err
+	}
+	cases, err := p.cases(opt.loadCase)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	for _, loadCase := range cases {
+		rezult, _, err := p.calculate(nil, loadCase, opt)
+		if err != nil {
+			return err
+		}
+		prefix := ""
+		if loadCase != nil {
+			prefix = loadCase.Name + "_"
+		}
+		for _, table := range reportTables {
+			err = writeReportTable(filepath.Join(dir, prefix+table.name), rezult, table.write)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeReportTable пишет таблицу в файл.
+func writeReportTable(fileName string, data str.Iterations, write func(w io.Writer, data str.Iterations) error) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	err = write(f, data)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	str "github.com/kenits/strength"
+
+	excel "github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+// writeSensitivity записывает таблицу чувствительности по убыванию влияния.
+func writeSensitivity(data []str.Sensitivity, height []float64, file *excel.File) error {
+	sheetName := "Чувствительность"
+	file.NewSheet(sheetName)
+	head := []string{
+		"Тип связи",
+		"№",
+		"Имя",
+		"Параметр",
+		"Значение",
+		"Производная предельного момента",
+		"Относительная чувствительность",
+	}
+	for _, val := range height {
+		head = append(head, fmt.Sprintf("Производная напряжения на высоте %v", val))
+	}
+	err := file.SetSheetRow(sheetName, "A1", &head)
+	if err != nil {
+		return err
+	}
+	for key, val := range data {
+		kind := "гибкая"
+		if val.Element.Rigid {
+			kind = "жёсткая"
+		}
+		row := []interface{}{
+			kind,
+			val.Element.ID,
+			val.Name,
+			val.Parameter.String(),
+			val.Value,
+			val.Moment,
+			val.Relative,
+		}
+		for _, strain := range val.Strain {
+			row = append(row, strain)
+		}
+		err = file.SetSheetRow(sheetName, fmt.Sprintf("A%d", key+2), &row)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+
+	str "github.com/kenits/strength"
+)
+
+// sweepMaxSteps наибольшее число значений ряда.
+const sweepMaxSteps = 1000
+
+// sweepRange ряд значений параметра.
+type sweepRange struct {
+	param    string  // age или moment
+	from, to float64 // первое и последнее значение
+	step     float64 // шаг
+}
+
+// sweepParams параметры ряда: заголовок столбца и установка значения в исходные данные.
+var sweepParams = map[string]struct {
+	header string
+	set    func(data *str.BaseData, val float64)
+}{
+	"age":    {"age [year]", func(data *str.BaseData, val float64) { data.Age = val }},
+	"moment": {"moment [kN*m]", func(data *str.BaseData, val float64) { data.Moment = math.Abs(val) }},
+}
+
+// values значения ряда от from до to включительно.
+func (r *sweepRange) values() ([]float64, error) {
+	if r.step <= 0 {
+		return nil, usageError("шаг -step должен быть больше нуля")
+	}
+	if r.to < r.from {
+		return nil, usageError("значение -to меньше -from")
+	}
+	n := int(math.Floor((r.to-r.from)/r.step+1e-9)) + 1
+	if n > sweepMaxSteps {
+		return nil, usageError(fmt.Sprintf("больше %d значений, увеличьте шаг", sweepMaxSteps))
+	}
+	rez := make([]float64, n)
+	for key := range rez {
+		rez[key] = r.from + float64(key)*r.step
+	}
+	return rez, nil
+}
+
+// sweepFile считает сечение для ряда значений срока службы или расчётного момента
+// и пишет итоговые характеристики в таблицу CSV, по строке на значение и случай загрузки.
+func sweepFile(args []string, opt *options) error {
+	if len(args) != 1 {
+		return usageError("необходимо одно имя файла")
+	}
+	param, ok := sweepParams[opt.sweep.param]
+	if !ok {
+		return usageError(fmt.Sprintf("неизвестный параметр -param %q, допустимы age и moment", opt.sweep.param))
+	}
+	values, err := opt.sweep.values()
+	if err != nil {
+		return err
+	}
+	p, err := readProject(args[0])
+	if err != nil {
+		return err
+	}
+	cases, err := p.cases(opt.loadCase)
+	if err != nil {
+		return err
+	}
+
+	records := [][]string{{
+		param.header,
+		"load_case",
+		"iterations",
+		"converged",
+		"area [cm2]",
+		"center_of_mass [m]",
+		"moment_of_inertia [cm2*m2]",
+		"limit_moment [kN*m]",
+		"max_strain [kN/cm2]",
+	}}
+	// сходимость каждого расчёта не печатается, чтобы не смешивать её с таблицей
+	o := *opt
+	o.quiet = true
+	for _, val := range values {
+		base := *p.baseData
+		param.set(&base, val)
+		for _, loadCase := range cases {
+			rezult, conv, err := p.calculate(&base, loadCase, &o)
+			if err != nil {
+				return err
+			}
+			records = append(records, sweepRecord(val, loadCase, rezult.Last(), &conv))
+		}
+	}
+
+	if opt.output == "" {
+		// таблица на экране без сообщений, чтобы её можно было перенаправить в файл
+		opt.quiet = true
+		return csv.NewWriter(os.Stdout).WriteAll(records)
+	}
+	f, err := os.Create(opt.output)
+	if err != nil {
+		return err
+	}
+	err = csv.NewWriter(f).WriteAll(records)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// sweepRecord строка таблицы ряда. Предельный момент пуст при заданном расчётном моменте, напряжение наоборот.
+func sweepRecord(val float64, loadCase *str.LoadCase, last str.Rezult, conv *str.Convergence) []string {
+	name := ""
+	if loadCase != nil {
+		name = loadCase.Name
+	}
+	rez := []string{
+		formatFloat(val),
+		name,
+		strconv.Itoa(conv.Iterations),
+		strconv.FormatBool(conv.Converged),
+		formatFloat(last.Area),
+		formatFloat(last.CenterOfMass),
+		formatFloat(last.MomentOfInertia),
+		"",
+		"",
+	}
+	if last.Moment != 0 {
+		rez[7] = formatFloat(last.Moment)
+	}
+	if len(last.Strain) != 0 {
+		max := 0.0
+		for _, s := range last.Strain {
+			max = math.Max(max, math.Abs(s))
+		}
+		rez[8] = formatFloat(max)
+	}
+	return rez
+}
+
+// formatFloat число в кратчайшей записи.
+func formatFloat(val float64) string {
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
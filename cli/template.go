@@ -0,0 +1,66 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/kenits/strength/model"
+)
+
+// exampleDocument пример исходных данных: днище, палуба и борт с одним случаем загрузки.
+func exampleDocument() *model.Document {
+	return &model.Document{
+		Version:  model.Version,
+		Project:  "Проект",
+		Name:     "Мидель-шпангоут",
+		Age:      20,
+		Symmetry: true,
+		Accuracy: 1,
+		Points: []model.Point{
+			{Height: 0, Strain: 23.5},
+			{Height: 10, Strain: 23.5},
+		},
+		Material: model.Material{Name: "сталь", ElasticModul: 206000000},
+		Rigid: []model.Rigid{
+			{ID: 1, Name: "днищевые продольные балки", Area: 30, Corrosion: 0.2, Height: 0.1, Count: 10},
+			{ID: 2, Name: "палубные продольные балки", Area: 30, Corrosion: 0.2, Height: 9.9, Count: 10},
+			{ID: 3, Name: "ширстрек", Area: 200, Corrosion: 0.5, Height: 5, Count: 1},
+		},
+		Flex: []model.Flex{
+			{ID: 1, Name: "днище", Length: 240, Width: 60, Thickness: 12, Corrosion: 0.1, Height: 0, Count: 10},
+			{ID: 2, Name: "палуба", Length: 240, Width: 60, Thickness: 10, Corrosion: 0.1, Height: 10, Count: 10},
+			{ID: 3, Name: "борт", Length: 60, Width: 240, Thickness: 10, Corrosion: 0.1, Height: 5, Count: 4, Pressure: 50},
+		},
+		LoadCases: []model.LoadCase{
+			{
+				Name:  "тихая вода",
+				Draft: 4,
+				Exposure: []model.Exposure{
+					{Flex: 1, Sea: true},
+					{Flex: 3, Sea: true},
+				},
+			},
+		},
+	}
+}
+
+// templateFile создаёт по расширению файла книгу Excel для заполнения с листом примера
+// или модель JSON/YAML с примером исходных данных.
+func templateFile(fileName string, opt *options) error {
+	_, isModel := model.FormatOf(fileName)
+	if !isModel && strings.ToLower(filepath.Ext(fileName)) != ".xlsx" {
+		return usageError("формат по расширению: .xlsx, .json, .yaml или .yml")
+	}
+	err := checkOverwrite(fileName, opt)
+	if err != nil {
+		return err
+	}
+	if isModel {
+		return model.WriteFile(fileName, exampleDocument())
+	}
+	file, err := newTemplate(nil, opt.full)
+	if err != nil {
+		return err
+	}
+	return file.SaveAs(fileName)
+}
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	str "github.com/kenits/strength"
+	"github.com/kenits/strength/model"
+
+	excel "github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+// templateRows последняя строка таблиц шаблона, до которой оформлены ячейки ввода и списки.
+const templateRows = 50
+
+// ruUnits русские обозначения единиц модели для заголовков шаблона.
+var ruUnits = map[string]string{
+	"m":        "м",
+	"cm":       "см",
+	"mm":       "мм",
+	"cm2":      "см2",
+	"cm2/year": "см2/год",
+	"mm/year":  "мм/год",
+	"kPa":      "кПа",
+	"kN/cm2":   "кН/см2",
+}
+
+// Стили шаблона.
+const (
+	styleTitle = `{"font":{"bold":true,"size":12}}`
+	styleHead  = `{"font":{"bold":true},"fill":{"type":"pattern","color":["#D9D9D9"],"pattern":1},` +
+		`"alignment":{"wrap_text":true,"vertical":"center"},` +
+		`"border":[{"type":"left","color":"#808080","style":1},{"type":"right","color":"#808080","style":1},` +
+		`{"type":"top","color":"#808080","style":1},{"type":"bottom","color":"#808080","style":1}]}`
+	styleInput = `{"fill":{"type":"pattern","color":["#FFF2CC"],"pattern":1},` +
+		`"border":[{"type":"left","color":"#BFBFBF","style":1},{"type":"right","color":"#BFBFBF","style":1},` +
+		`{"type":"top","color":"#BFBFBF","style":1},{"type":"bottom","color":"#BFBFBF","style":1}]}`
+	styleNote = `{"font":{"italic":true,"color":"#7F7F7F"}}`
+)
+
+// templateWriter пишет лист шаблона, после первой ошибки ничего не делает.
+type templateWriter struct {
+	file   *excel.File
+	sheet  string
+	styles map[string]int // стили по описаниям
+	err    error
+}
+
+// style номер стиля по описанию, стили создаются один раз на книгу.
+func (w *templateWriter) style(format string) int {
+	if id, ok := w.styles[format]; ok || w.err != nil {
+		return id
+	}
+	id, err := w.file.NewStyle(format)
+	w.err = err
+	w.styles[format] = id
+	return id
+}
+
+// row пишет значения в строку начиная с ячейки addr.
+func (w *templateWriter) row(addr string, vals ...interface{}) {
+	if w.err == nil {
+		w.err = w.file.SetSheetRow(w.sheet, addr, &vals)
+	}
+}
+
+// format оформляет область от hcell до vcell.
+func (w *templateWriter) format(hcell, vcell, format string) {
+	id := w.style(format)
+	if w.err == nil {
+		w.err = w.file.SetCellStyle(w.sheet, hcell, vcell, id)
+	}
+}
+
+// width задаёт ширину столбцов от startcol до endcol.
+func (w *templateWriter) width(startcol, endcol string, width float64) {
+	if w.err == nil {
+		w.err = w.file.SetColWidth(w.sheet, startcol, endcol, width)
+	}
+}
+
+// dropList добавляет выпадающий список значений в область sqref, значение не из списка вызывает предупреждение.
+func (w *templateWriter) dropList(sqref string, vals ...string) {
+	if w.err != nil {
+		return
+	}
+	dv := excel.NewDataValidation(true)
+	dv.SetSqref(sqref)
+	dv.Operator = "between" // пустой оператор Excel считает ошибкой книги
+	dv.SetError(excel.DataValidationErrorStyleWarning, "Значение не из списка", "Допустимые значения: "+strings.Join(vals, ", "))
+	w.err = dv.SetDropList(vals)
+	if w.err == nil {
+		w.err = w.file.AddDataValidation(w.sheet, dv)
+	}
+}
+
+// table пишет заголовок таблицы в строку row начиная со столбца col и строки rows под ним.
+// Ячейки ввода оформляются до строки last, если она ниже последней строки rows.
+// Возвращает строку за таблицей.
+func (w *templateWriter) table(col, row int, head []string, rows [][]interface{}, last int) int {
+	first := cellName(col, row)
+	w.row(first, toRow(head)...)
+	w.format(first, cellName(col+len(head)-1, row), styleHead)
+	if end := row + len(rows); end > last {
+		last = end
+	}
+	if last > row {
+		w.format(cellName(col, row+1), cellName(col+len(head)-1, last), styleInput)
+	}
+	for key, val := range rows {
+		w.row(cellName(col, row+1+key), val...)
+	}
+	return last + 1
+}
+
+// title пишет заголовок раздела в ячейку addr.
+func (w *templateWriter) title(addr, text string) {
+	w.row(addr, text)
+	w.format(addr, addr, styleTitle)
+}
+
+// note пишет пояснение в ячейку addr.
+func (w *templateWriter) note(addr, text string) {
+	w.row(addr, text)
+	w.format(addr, addr, styleNote)
+}
+
+// cellName адрес ячейки по столбцу и строке с 1.
+func cellName(col, row int) string {
+	return model.ColumnName(col) + strconv.Itoa(row)
+}
+
+// toRow строки как значения строки листа.
+func toRow(vals []string) []interface{} {
+	rez := make([]interface{}, len(vals))
+	for key, val := range vals {
+		rez[key] = val
+	}
+	return rez
+}
+
+// optional значение для необязательной ячейки: пусто вместо нуля.
+func optional(val float64) interface{} {
+	if val == 0 {
+		return nil
+	}
+	return val
+}
+
+// columnHeader заголовок столбца таблицы: русское имя с заглавной буквы и единица модели.
+func columnHeader(c *model.Column) string {
+	name := []rune(c.Name)
+	if len(c.Aliases) != 0 {
+		name = []rune(c.Aliases[0])
+	}
+	name[0] = unicode.ToUpper(name[0])
+	if c.Unit == "" {
+		return string(name)
+	}
+	return string(name) + ", " + ruUnits[c.Unit]
+}
+
+// columnsHeader заголовки столбцов таблицы.
+func columnsHeader(columns []model.Column) []string {
+	rez := make([]string, len(columns))
+	for key := range columns {
+		rez[key] = columnHeader(&columns[key])
+	}
+	return rez
+}
+
+// columnName имя столбца листа поля name таблицы, начинающейся со столбца A.
+func columnName(columns []model.Column, name string) string {
+	for key := range columns {
+		if columns[key].Name == name {
+			return model.ColumnName(key + 1)
+		}
+	}
+	return ""
+}
+
+// sideNames русские названия бортов для выпадающего списка.
+var sideNames = []string{"оба", "дп", "левый", "правый"}
+
+// templateBase пишет параметры по подписям и таблицу расчётных точек начиная со строки row,
+// значения из doc, пустые если doc nil. Возвращает строку за таблицей точек.
+func templateBase(w *templateWriter, doc *model.Document, row, last int) int {
+	labels := []string{
+		"Проект",
+		"Имя расчёта",
+		"Срок службы, лет",
+		"Расчётный момент, кН*м",
+		"Изгиб",
+		"Симметрия",
+		"Точность, %",
+		"Модуль упругости, кПа",
+	}
+	notes := []string{"", "", "", "пусто — считается предельный момент", "прогиб или перегиб", "да — считается половина сечения", "", ""}
+	vals := make([]interface{}, len(labels))
+	var points [][]interface{}
+	if doc != nil {
+		bending, symmetry := "прогиб", "нет"
+		if doc.Hogging {
+			bending = "перегиб"
+		}
+		if doc.Symmetry {
+			symmetry = "да"
+		}
+		vals = []interface{}{doc.Project, doc.Name, doc.Age, optional(doc.Moment), bending, symmetry,
+			doc.Accuracy, doc.Material.ElasticModul}
+		for _, val := range doc.Points {
+			points = append(points, []interface{}{val.Height, val.Strain})
+		}
+	}
+	for key := range labels {
+		w.row(fmt.Sprintf("A%d", row+key), labels[key], vals[key], notes[key])
+	}
+	end := row + len(labels) - 1
+	w.format(fmt.Sprintf("B%d", row), fmt.Sprintf("B%d", end), styleInput)
+	w.format(fmt.Sprintf("C%d", row), fmt.Sprintf("C%d", end), styleNote)
+	w.dropList(fmt.Sprintf("B%d", row+4), "прогиб", "перегиб")
+	w.dropList(fmt.Sprintf("B%d", row+5), "да", "нет")
+
+	w.title(fmt.Sprintf("A%d", end+2), "Расчётные точки")
+	next := w.table(1, end+3, columnsHeader(model.PointColumns), points, last)
+	w.width("A", "A", 26)
+	w.width("B", "B", 30)
+	w.width("C", "C", 36)
+	return next
+}
+
+// templateRigid пишет таблицу жёстких связей со строки row, связи из doc если не nil.
+func templateRigid(w *templateWriter, doc *model.Document, row, last int) int {
+	var rows [][]interface{}
+	if doc != nil {
+		for _, val := range doc.Rigid {
+			rows = append(rows, []interface{}{val.ID, val.Name, val.Area, optional(val.Corrosion), val.Height,
+				optional(val.HalfBreadth), val.Side, val.Count})
+		}
+	}
+	next := w.table(1, row, columnsHeader(model.RigidColumns), rows, last)
+	side := columnName(model.RigidColumns, "side")
+	w.dropList(fmt.Sprintf("%s%d:%s%d", side, row+1, side, next-1), sideNames...)
+	w.width("A", "A", 6)
+	w.width("B", "B", 28)
+	w.width("C", "H", 14)
+	return next
+}
+
+// templateFlex пишет таблицу гибких связей со строки row, связи из doc если не nil.
+func templateFlex(w *templateWriter, doc *model.Document, row, last int) int {
+	var rows [][]interface{}
+	if doc != nil {
+		for _, val := range doc.Flex {
+			rows = append(rows, []interface{}{val.ID, val.Name, val.Length, val.Width, val.Thickness,
+				optional(val.Corrosion), val.Height, optional(val.HalfBreadth), val.Side, val.Count, optional(val.Pressure)})
+		}
+	}
+	next := w.table(1, row, columnsHeader(model.FlexColumns), rows, last)
+	side := columnName(model.FlexColumns, "side")
+	w.dropList(fmt.Sprintf("%s%d:%s%d", side, row+1, side, next-1), sideNames...)
+	w.width("A", "A", 6)
+	w.width("B", "B", 20)
+	w.width("C", "K", 13)
+	return next
+}
+
+// templatePressure пишет лист нагрузок в фиксированных ячейках readLoadCase.
+func templatePressure(w *templateWriter) {
+	params := [][]interface{}{
+		{"Осадка, м", nil, ""},
+		{"Плотность забортной воды, т/м3", nil, fmt.Sprintf("пусто — %v", str.SeaDensity)},
+		{"Длина судна, м", nil, "для волновой нагрузки, пусто — тихая вода"},
+		{"Скорость хода, уз", nil, ""},
+		{"Коэффициент распределения по длине", nil, ""},
+	}
+	for key, val := range params {
+		w.row(fmt.Sprintf("A%d", key+1), val...)
+	}
+	w.format("B1", "B5", styleInput)
+	w.format("C1", "C5", styleNote)
+	w.table(1, pressureStartRow-1, []string{
+		"№ гибкой связи",
+		"Наружная обшивка",
+		"Уровень налива, м",
+		"Плотность груза, т/м3",
+		"Избыточное давление, кПа",
+	}, nil, templateRows)
+	w.dropList(fmt.Sprintf("B%d:B%d", pressureStartRow, templateRows), "да", "нет")
+	w.width("A", "A", 34)
+	w.width("B", "E", 16)
+}
+
+// templateGauging пишет лист замеров толщин в фиксированных ячейках readGauging.
+func templateGauging(w *templateWriter) {
+	w.row("A1", "Допускаемый износ, %")
+	w.row("A2", "Толщина для расчёта", "средняя")
+	w.format("B1", "B2", styleInput)
+	w.dropList("B2", "средняя", "минимальная")
+	w.table(1, gaugingStartRow-1, []string{
+		"Тип связи",
+		"№",
+		"Построечная толщина, мм",
+		"Замер 1, мм",
+		"Замер 2, мм",
+		"Замер 3, мм",
+	}, nil, templateRows)
+	w.dropList(fmt.Sprintf("A%d:A%d", gaugingStartRow, templateRows), "жёсткая", "гибкая")
+	w.width("A", "A", 22)
+	w.width("B", "F", 14)
+}
+
+// templateDeflection пишет лист изгиба корпуса в фиксированных ячейках readStations и readSupport.
+func templateDeflection(w *templateWriter) {
+	w.table(1, 1, []string{"Абсцисса, м", "Изгибающий момент, кН*м", "Момент инерции, см2*м2"}, nil, templateRows)
+	w.row("H1", "Опоры", "по концам")
+	w.format("I1", "I1", styleInput)
+	w.dropList("I1", "по концам", "заделка в корме", "заделка в носу")
+	w.note("H2", "Момент инерции можно не задавать: берётся рассчитанный для сечения")
+	w.width("A", "C", 18)
+}
+
+// templateFatigue пишет лист усталости в фиксированных ячейках readFatigueLoad и readFatigueDetails.
+func templateFatigue(w *templateWriter) {
+	labels := []string{
+		"Размах изгибающего момента, кН*м",
+		"Параметр формы распределения",
+		"Число циклов",
+		"Срок службы, лет",
+	}
+	for key, val := range labels {
+		w.row(fmt.Sprintf("A%d", key+1), val)
+	}
+	w.format("B1", "B4", styleInput)
+	w.table(1, fatigueStartRow-1, []string{
+		"№ жёсткой связи",
+		"Шпация, м",
+		"Пролёт, м",
+		"Момент сопротивления, см3",
+		"Размах давления, кПа",
+		"Тип соединения",
+		"Коэффициент концентрации",
+		"Кривая усталости",
+	}, nil, templateRows)
+	connections := make([]string, 0, len(connectionTypes))
+	for name := range connectionTypes {
+		connections = append(connections, name)
+	}
+	sort.Strings(connections)
+	curves := make([]string, 0, len(str.SNCurves))
+	for name := range str.SNCurves {
+		curves = append(curves, name)
+	}
+	sort.Strings(curves)
+	w.dropList(fmt.Sprintf("F%d:F%d", fatigueStartRow, templateRows), connections...)
+	w.dropList(fmt.Sprintf("H%d:H%d", fatigueStartRow, templateRows), curves...)
+	w.width("A", "A", 34)
+	w.width("B", "H", 16)
+}
+
+// templateExample пишет пример заполнения исходных данных и таблиц связей.
+// Лист примера при расчёте не читается.
+func templateExample(w *templateWriter) {
+	doc := exampleDocument()
+	w.title("A1", fmt.Sprintf("Пример заполнения листа «%s»", baseSheet))
+	row := templateBase(w, doc, 2, 0)
+	w.title(fmt.Sprintf("A%d", row+1), "Пример заполнения листа «Жёсткие связи»")
+	row = templateRigid(w, doc, row+2, 0)
+	w.title(fmt.Sprintf("A%d", row+1), "Пример заполнения листа «Гибкие связи»")
+	templateFlex(w, doc, row+2, 0)
+	w.width("C", "K", 14)
+}
+
+// exampleSheet лист примера заполнения.
+const exampleSheet = "Пример"
+
+// newTemplate создаёт книгу исходных данных: листы ввода с заголовками, единицами, списками
+// и оформленными ячейками ввода, лист примера. Таблицы заполняются из doc, пустые если doc nil.
+// Листы нагрузок, изгиба, усталости и замеров добавляются при full: их наличие включает эти расчёты.
+func newTemplate(doc *model.Document, full bool) (*excel.File, error) {
+	sheets := []struct {
+		name  string
+		write func(w *templateWriter)
+	}{
+		{baseSheet, func(w *templateWriter) { templateBase(w, doc, 2, templateRows) }},
+		{"Жёсткие связи", func(w *templateWriter) { templateRigid(w, doc, 1, templateRows) }},
+		{"Гибкие связи", func(w *templateWriter) { templateFlex(w, doc, 1, templateRows) }},
+	}
+	if full {
+		sheets = append(sheets, []struct {
+			name  string
+			write func(w *templateWriter)
+		}{
+			{pressureSheet, templatePressure},
+			{deflectionSheet, templateDeflection},
+			{fatigueSheet, templateFatigue},
+			{gaugingSheet, templateGauging},
+		}...)
+	}
+	sheets = append(sheets, struct {
+		name  string
+		write func(w *templateWriter)
+	}{exampleSheet, templateExample})
+
+	file := excel.NewFile()
+	styles := make(map[string]int)
+	for key, sheet := range sheets {
+		if key == 0 {
+			file.SetSheetName("Sheet1", sheet.name)
+		} else {
+			file.NewSheet(sheet.name)
+		}
+		w := templateWriter{file: file, sheet: sheet.name, styles: styles}
+		if sheet.name == baseSheet {
+			w.title("A1", "Исходные данные расчёта общей прочности")
+		}
+		sheet.write(&w)
+		if w.err != nil {
+			return nil, fmt.Errorf("%s: %v", sheet.name, w.err)
+		}
+	}
+	file.SetActiveSheet(file.GetSheetIndex(baseSheet))
+	return file, nil
+}
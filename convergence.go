@@ -0,0 +1,229 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+)
+
+// Acceleration способ ускорения сходимости приближений.
+type Acceleration int
+
+const (
+	// AccelerationNone следующее приближение строится прямо по потерям предыдущего.
+	AccelerationNone Acceleration = iota
+	// AccelerationRelaxation нижняя релаксация потерь с коэффициентом Options.Relaxation.
+	AccelerationRelaxation
+	// AccelerationAitken Δ²-процесс Эйткена по трём последовательным приближениям.
+	AccelerationAitken
+	// AccelerationSecant метод секущих по невязке двух последних приближений.
+	AccelerationSecant
+)
+
+// String название способа ускорения.
+func (a Acceleration) String() string {
+	switch a {
+	case AccelerationNone:
+		return "без ускорения"
+	case AccelerationRelaxation:
+		return "релаксация"
+	case AccelerationAitken:
+		return "Эйткен"
+	case AccelerationSecant:
+		return "секущие"
+	}
+	return "неизвестно"
+}
+
+// DefaultMaxIterations наибольшее число приближений по умолчанию.
+const DefaultMaxIterations = 1000
+
+// Options параметры итерационного расчёта.
+type Options struct {
+	Acceleration  Acceleration // способ ускорения
+	Relaxation    float64      // коэффициент релаксации от 0 до 1, если 0 то 0.5
+	MaxIterations int          // наибольшее число приближений, если 0 то DefaultMaxIterations, меньше 0 без ограничения
+	Observer      Observer     // получатель приближений по мере расчёта, может быть nil
+	Workers       int          // количество параллельных расчётов пластин, если 0 или 1 то последовательно
+}
+
+// validate проверяет параметры итерационного расчёта.
+func (o *Options) validate() error {
+	if !(o.Relaxation >= 0 && o.Relaxation <= 1) {
+		return fmt.Errorf("relaxation must be in (0, 1] or 0 for default, got %v", o.Relaxation)
+	}
+	return nil
+}
+
+// Observer получает результаты приближений по мере расчёта, например для отображения хода расчёта.
+// Вызывается в той же горутине что и расчёт, данные нельзя изменять.
+type Observer interface {
+	// Observe получает приближение, в первом приближении пластин нет.
+	Observe(it *Iteration)
+}
+
+// ObserverFunc функция как Observer.
+type ObserverFunc func(it *Iteration)
+
+// Observe вызывает f.
+func (f ObserverFunc) Observe(it *Iteration) {
+	f(it)
+}
+
+// observe передаёт приближение получателю если он задан.
+func (o *Options) observe(it *Iteration) {
+	if o.Observer != nil {
+		o.Observer.Observe(it)
+	}
+}
+
+// Convergence сведения о сходимости расчёта.
+type Convergence struct {
+	Acceleration Acceleration // способ ускорения
+	Iterations   int          // число приближений включая первое
+	Converged    bool         // точность достигнута до исчерпания числа приближений
+	Trace        []TraceStep  // изменения результата по приближениям начиная со второго
+}
+
+// TraceStep изменение результата приближения относительно предыдущего.
+type TraceStep struct {
+	Iteration       int       // номер приближения
+	CenterOfMass    float64   // положение нейтральной оси м
+	AxisResidual    float64   // смещение нейтральной оси м
+	MomentOfInertia float64   // момент инерции см2*м2
+	InertiaResidual float64   // изменение момента инерции %
+	Moment          float64   // предельный момент кН*м, 0 если задан расчётный
+	MomentResidual  float64   // изменение предельного момента %
+	Strain          []float64 // напряжения в расчётных точках кН/см2, при заданном моменте
+	StrainResidual  []float64 // изменения напряжений %
+	Criterion       float64   // значение критерия точности %
+	Changed         []int     // ключи пластин у которых изменился редукционный коэффициент, в порядке приближений
+}
+
+// reducingTolerance изменение редукционного коэффициента, ниже которого он считается неизменным.
+const reducingTolerance = 1e-9
+
+// createTraceStep сравнивает приближение с предыдущим.
+// В первом приближении пластины не редуцированы, коэффициент принимается равным 1.
+func createTraceStep(old, new *Iteration, moment float64) TraceStep {
+	step := TraceStep{
+		Iteration:       new.ID,
+		CenterOfMass:    new.Rezult.CenterOfMass,
+		AxisResidual:    new.Rezult.CenterOfMass - old.Rezult.CenterOfMass,
+		MomentOfInertia: new.Rezult.MomentOfInertia,
+		InertiaResidual: relativeDifference(old.Rezult.MomentOfInertia, new.Rezult.MomentOfInertia),
+		Moment:          new.Rezult.Moment,
+		Criterion:       accuracyCriterion(&old.Rezult, &new.Rezult, moment),
+	}
+	if moment == 0 {
+		step.MomentResidual = relativeDifference(old.Rezult.Moment, new.Rezult.Moment)
+	}
+	if len(new.Rezult.Strain) != 0 {
+		step.Strain = append([]float64(nil), new.Rezult.Strain...)
+		step.StrainResidual = make([]float64, len(new.Rezult.Strain))
+		for key := range old.Rezult.Strain {
+			step.StrainResidual[key] = relativeDifference(old.Rezult.Strain[key], new.Rezult.Strain[key])
+		}
+	}
+	// состав пластин во всех приближениях одинаков
+	for key, val := range new.Approx {
+		prev := 1.0
+		if len(old.Approx) == len(new.Approx) {
+			prev = old.Approx[key].Reducing
+		}
+		if math.Abs(val.Reducing-prev) > reducingTolerance {
+			step.Changed = append(step.Changed, val.Key)
+		}
+	}
+	return step
+}
+
+// accelerator строит потери для следующего приближения по потерям предыдущих.
+// Ускоряются суммы потерь площади и моментов, по ним положение нейтральной оси и момент инерции.
+type accelerator struct {
+	opts  Options
+	prevX []float64 // предыдущие входные потери для секущих или исходные для Эйткена
+	prevR []float64 // предыдущая невязка для секущих или первое отображение для Эйткена
+}
+
+// next возвращает потери для следующего приближения, x потери с которыми считалось приближение,
+// g потери полученные по приближению.
+func (a *accelerator) next(x, g sectionSums) sectionSums {
+	switch a.opts.Acceleration {
+	case AccelerationRelaxation:
+		omega := a.opts.Relaxation
+		if omega == 0 {
+			omega = 0.5
+		}
+		xv, gv := x.vector(), g.vector()
+		for i := range xv {
+			xv[i] += omega * (gv[i] - xv[i])
+		}
+		return fromVector(xv)
+	case AccelerationAitken:
+		return a.aitken(x, g)
+	case AccelerationSecant:
+		return a.secant(x, g)
+	}
+	return g
+}
+
+// aitken чередует шаг простой итерации и шаг Δ²-процесса по компонентам.
+func (a *accelerator) aitken(x, g sectionSums) sectionSums {
+	if a.prevX == nil {
+		a.prevX, a.prevR = x.vector(), g.vector()
+		return g
+	}
+	x0, g1, g2 := a.prevX, a.prevR, g.vector()
+	a.prevX, a.prevR = nil, nil
+	rez := make([]float64, len(g2))
+	for i := range rez {
+		rez[i] = g2[i]
+		d := g2[i] - 2*g1[i] + x0[i]
+		if math.Abs(d) > accelerationTolerance*math.Abs(g2[i]) {
+			rez[i] = g2[i] - math.Pow(g2[i]-g1[i], 2)/d
+		}
+	}
+	return fromVector(rez)
+}
+
+// secant обновляет каждую компоненту по секущей к невязке g - x.
+func (a *accelerator) secant(x, g sectionSums) sectionSums {
+	xv, gv := x.vector(), g.vector()
+	r := make([]float64, len(xv))
+	for i := range r {
+		r[i] = gv[i] - xv[i]
+	}
+	rez := gv
+	if a.prevX != nil {
+		rez = make([]float64, len(xv))
+		for i := range rez {
+			rez[i] = gv[i]
+			dr := r[i] - a.prevR[i]
+			if math.Abs(dr) > accelerationTolerance*math.Abs(gv[i]) {
+				rez[i] = xv[i] - r[i]*(xv[i]-a.prevX[i])/dr
+			}
+		}
+	}
+	a.prevX, a.prevR = xv, r
+	return fromVector(rez)
+}
+
+// accelerationTolerance относительная величина знаменателя, ниже которой ускорение не применяется.
+const accelerationTolerance = 1e-12
+
+// vector суммы в виде вектора.
+func (s *sectionSums) vector() []float64 {
+	return []float64{s.area, s.staticMoment, s.staticMomentY, s.momentOfInertia, s.momentOfInertiaY, s.productOfInertia}
+}
+
+// fromVector суммы из вектора.
+func fromVector(v []float64) sectionSums {
+	return sectionSums{
+		area:             v[0],
+		staticMoment:     v[1],
+		staticMomentY:    v[2],
+		momentOfInertia:  v[3],
+		momentOfInertiaY: v[4],
+		productOfInertia: v[5],
+	}
+}
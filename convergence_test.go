@@ -0,0 +1,210 @@
+package strength
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func Test_accelerator_next(t *testing.T) {
+	// линейное отображение g = 0.5x + 10 с неподвижной точкой 20
+	g := func(x sectionSums) sectionSums {
+		v := x.vector()
+		for i := range v {
+			v[i] = 0.5*v[i] + 10
+		}
+		return fromVector(v)
+	}
+	tests := []struct {
+		name  string
+		opts  Options
+		steps int
+		want  float64
+	}{
+		{name: "none", steps: 2, want: 15},
+		{name: "relaxation", opts: Options{Acceleration: AccelerationRelaxation, Relaxation: 0.5}, steps: 1, want: 5},
+		{name: "aitken exact on linear map", opts: Options{Acceleration: AccelerationAitken}, steps: 2, want: 20},
+		{name: "secant exact on linear map", opts: Options{Acceleration: AccelerationSecant}, steps: 2, want: 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acc := accelerator{opts: tt.opts}
+			var x sectionSums
+			for i := 0; i < tt.steps; i++ {
+				x = acc.next(x, g(x))
+			}
+			for _, val := range x.vector() {
+				if math.Abs(val-tt.want) > 1e-12 {
+					t.Errorf("accelerator.next() = %v, want %v", x, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+// convergenceModel небольшое сечение с поперечной пластиной под давлением.
+func convergenceModel() (BaseData, map[int]Rigid, map[int]Flex) {
+	base := BaseData{Height: []float64{0, 10}, Strain: []float64{23.5, 23.5}, ElasticModul: 2.06e8, Symmetry: true, Moment: 3e5, Accuracy: 1e-6}
+	rigid := map[int]Rigid{
+		1: {ID: 1, AreaStart: 30, Height: 0.1, Count: 10},
+		2: {ID: 2, AreaStart: 30, Height: 9.9, Count: 10},
+		3: {ID: 3, AreaStart: 200, Height: 5, Count: 1},
+	}
+	flex := map[int]Flex{
+		1: {ID: 1, Length: 240, Width: 60, ThicknessStart: 8, Count: 10},
+		2: {ID: 2, Length: 240, Width: 60, ThicknessStart: 8, Height: 10, Count: 10},
+		3: {ID: 3, Length: 60, Width: 240, ThicknessStart: 8, Height: 5, Count: 4, Pressure: 50},
+	}
+	CalcAllRigid(rigid, base.Age)
+	CalcAllFlex(flex, base.Age)
+	return base, rigid, flex
+}
+
+func TestCalculateWithOptions(t *testing.T) {
+	base, rigid, flex := convergenceModel()
+
+	plain := Calculate(&base, rigid, flex)
+	want := plain.Last()
+	for a := AccelerationNone; a <= AccelerationSecant; a++ {
+		t.Run(a.String(), func(t *testing.T) {
+			rezult, conv, err := CalculateWithOptions(&base, rigid, flex, &Options{Acceleration: a})
+			if err != nil {
+				t.Fatalf("CalculateWithOptions() error = %v", err)
+			}
+			if !conv.Converged || conv.Iterations != len(rezult) || len(conv.Trace) != conv.Iterations-1 {
+				t.Fatalf("CalculateWithOptions() convergence = %+v", conv)
+			}
+			got := rezult.Last()
+			if math.Abs(got.MomentOfInertia-want.MomentOfInertia) > 1e-4*want.MomentOfInertia {
+				t.Errorf("CalculateWithOptions() moment of inertia = %v, want %v", got.MomentOfInertia, want.MomentOfInertia)
+			}
+			if a == AccelerationNone && conv.Iterations != len(plain) {
+				t.Errorf("CalculateWithOptions() iterations = %v, want %v", conv.Iterations, len(plain))
+			}
+		})
+	}
+
+	_, conv, _ := CalculateWithOptions(&base, rigid, flex, &Options{MaxIterations: 2})
+	if conv.Converged || conv.Iterations != 2 {
+		t.Errorf("CalculateWithOptions() limited convergence = %+v", conv)
+	}
+
+	// без ограничения считается как Calculate до достижения точности
+	unlimited, conv, _ := CalculateWithOptions(&base, rigid, flex, &Options{MaxIterations: -1})
+	if !conv.Converged || len(unlimited) != len(plain) || unlimited.Last().MomentOfInertia != want.MomentOfInertia {
+		t.Errorf("CalculateWithOptions() unlimited convergence = %+v, iterations %d, want %d", conv, len(unlimited), len(plain))
+	}
+}
+
+func TestCalculateWithOptions_relaxation(t *testing.T) {
+	base, rigid, flex := convergenceModel()
+	tests := []struct {
+		relaxation float64
+		wantErr    bool
+	}{
+		{relaxation: 0},
+		{relaxation: 0.3},
+		{relaxation: 1},
+		{relaxation: -0.5, wantErr: true},
+		{relaxation: 1.5, wantErr: true},
+		{relaxation: math.NaN(), wantErr: true},
+	}
+	for _, tt := range tests {
+		opts := Options{Acceleration: AccelerationRelaxation, Relaxation: tt.relaxation}
+		rezult, conv, err := CalculateWithOptions(&base, rigid, flex, &opts)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CalculateWithOptions(relaxation %v) error = %v, wantErr %v", tt.relaxation, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !conv.Converged {
+			t.Errorf("CalculateWithOptions(relaxation %v) convergence = %+v", tt.relaxation, conv)
+		}
+		if _, _, err := CalculateContext(context.Background(), &base, rigid, flex, &opts); (err != nil) != tt.wantErr {
+			t.Errorf("CalculateContext(relaxation %v) error = %v, wantErr %v", tt.relaxation, err, tt.wantErr)
+		}
+		if tt.wantErr && rezult != nil {
+			t.Errorf("CalculateWithOptions(relaxation %v) results on error", tt.relaxation)
+		}
+	}
+}
+
+func Test_createTraceStep(t *testing.T) {
+	old := Iteration{
+		ID:     2,
+		Rezult: Rezult{CenterOfMass: 5, MomentOfInertia: 100, Strain: []float64{10, -20}},
+		Approx: []Approx{{Key: 1, Reducing: 0.5}, {Key: -1, Reducing: 0.5}, {Key: 2, Reducing: 0.7}, {Key: 3, Reducing: 0.9}},
+	}
+	new := Iteration{
+		ID:     3,
+		Rezult: Rezult{CenterOfMass: 4.9, MomentOfInertia: 80, Strain: []float64{11, -20}},
+		Approx: []Approx{{Key: 1, Reducing: 0.5}, {Key: -1, Reducing: 0.4}, {Key: 2, Reducing: 0.6}, {Key: 3, Reducing: 0.9}},
+	}
+
+	got := createTraceStep(&old, &new, 1)
+	if math.Abs(got.AxisResidual+0.1) > 1e-12 || got.InertiaResidual != 25 {
+		t.Errorf("createTraceStep() residuals = %v, %v", got.AxisResidual, got.InertiaResidual)
+	}
+	if math.Abs(got.StrainResidual[0]-100.0/11) > 1e-12 || got.StrainResidual[1] != 0 || got.Criterion != got.StrainResidual[0] {
+		t.Errorf("createTraceStep() strain residuals = %v, criterion %v", got.StrainResidual, got.Criterion)
+	}
+	if len(got.Changed) != 2 || got.Changed[0] != -1 || got.Changed[1] != 2 {
+		t.Errorf("createTraceStep() changed = %v, want [-1 2]", got.Changed)
+	}
+
+	// во втором приближении пластины сравниваются с нередуцированными
+	first := Iteration{ID: 1, Rezult: old.Rezult}
+	got = createTraceStep(&first, &new, 1)
+	if len(got.Changed) != 4 {
+		t.Errorf("createTraceStep() changed = %v, want all plates", got.Changed)
+	}
+}
+
+func TestCalculateContext(t *testing.T) {
+	base, rigid, flex := convergenceModel()
+
+	var ids []int
+	observer := ObserverFunc(func(it *Iteration) {
+		if (it.ID == 1) != (it.Approx == nil) {
+			t.Errorf("Observe() approximation %d approx = %v", it.ID, it.Approx)
+		}
+		ids = append(ids, it.ID)
+	})
+	rezult, conv, err := CalculateContext(context.Background(), &base, rigid, flex, &Options{Observer: observer})
+	if err != nil {
+		t.Fatalf("CalculateContext() error = %v", err)
+	}
+	if len(ids) != len(rezult) {
+		t.Fatalf("Observe() calls = %v, want %d", ids, len(rezult))
+	}
+	for key, val := range ids {
+		if val != key+1 {
+			t.Errorf("Observe() calls = %v not in order", ids)
+		}
+	}
+
+	// отмена из получателя после второго приближения
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stop := ObserverFunc(func(it *Iteration) {
+		if it.ID == 2 {
+			cancel()
+		}
+	})
+	rezult, conv, err = CalculateContext(ctx, &base, rigid, flex, &Options{Observer: stop})
+	if err != context.Canceled {
+		t.Fatalf("CalculateContext() error = %v, want %v", err, context.Canceled)
+	}
+	if len(rezult) != 2 || conv.Iterations != 2 || conv.Converged {
+		t.Errorf("CalculateContext() cancelled with %d results, %+v", len(rezult), conv)
+	}
+
+	// отменённый заранее контекст прерывает расчёт пластин
+	rezult, _, err = CalculateContext(ctx, &base, rigid, flex, &Options{})
+	if err != context.Canceled || len(rezult) != 1 {
+		t.Errorf("CalculateContext() error = %v with %d results", err, len(rezult))
+	}
+	if _, err := createAllApprox(ctx, buildMembers(rigid, flex, true), &section{}, 0, base.ElasticModul, false, 0); err != context.Canceled {
+		t.Errorf("createAllApprox() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -1,7 +1,9 @@
 package strength
 
 import (
+	"context"
 	"math"
+	"sort"
 )
 
 // BaseData исходные данные по проекту.
@@ -12,12 +14,49 @@ type BaseData struct {
 	Age            float64   // срок службы судна лет
 	Height, Strain []float64 // расчётные точки по высотам с допускаемыми напряжениями м и кН/см2
 	ElasticModul   float64   // модуль упругости материала кПа
-	Symmetry       bool      // признак симетрии
+	Symmetry       bool      // признак симетрии, связи без указания борта задают половину сечения
 	MomentFlag     bool      // false прогиб, true перегиб
 	Moment         float64   // расчётный момент всегда положительный (если не задан то считается предельный) кН*м
 	Accuracy       float64   // точность расчёт в %
 }
 
+// SteelDensity плотность стали т/м3.
+const SteelDensity = 7.85
+
+// ElementRef ссылка на связь сечения.
+type ElementRef struct {
+	Rigid bool // true жёсткая связь, false гибкая
+	ID    int  // номер связи
+}
+
+// lessRef порядок связей: сначала жёсткие, затем гибкие, по возрастанию номера.
+func lessRef(a, b ElementRef) bool {
+	if a.Rigid != b.Rigid {
+		return a.Rigid
+	}
+	return a.ID < b.ID
+}
+
+// RigidIDs возвращает номера жёстких связей по возрастанию для обхода карты в постоянном порядке.
+func RigidIDs(data map[int]Rigid) []int {
+	rez := make([]int, 0, len(data))
+	for id := range data {
+		rez = append(rez, id)
+	}
+	sort.Ints(rez)
+	return rez
+}
+
+// FlexIDs возвращает номера гибких связей по возрастанию для обхода карты в постоянном порядке.
+func FlexIDs(data map[int]Flex) []int {
+	rez := make([]int, 0, len(data))
+	for id := range data {
+		rez = append(rez, id)
+	}
+	sort.Ints(rez)
+	return rez
+}
+
 // calcAreaEnd считает площадь на срок службы.
 func calcAreaEnd(area, corrosion, age float64) float64 {
 	rez := area - age*corrosion
@@ -40,48 +79,111 @@ func calcMomentOfInertia(area, height float64) float64 {
 // accuracyCheck проверяет точности, если точность удовлетворительная -> true.
 // moment как индикатор если 0 то проверка по предельному моменту иначе по напряжениям.
 func accuracyCheck(old, new *Rezult, accuracy, moment float64) bool {
-	var difference float64
-	flag := true
+	return !(accuracyCriterion(old, new, moment) > accuracy)
+}
+
+// accuracyCriterion наибольшее относительное изменение результата в %, по которому проверяется точность.
+// moment как индикатор если 0 то по предельному моменту иначе по напряжениям.
+func accuracyCriterion(old, new *Rezult, moment float64) float64 {
+	var rez float64
 
 	if moment != 0 {
 
 		for i := range old.Strain {
-			difference = math.Abs((new.Strain[i] - old.Strain[i]) / new.Strain[i] * 100)
-			if difference > accuracy {
-				flag = false
+			difference := relativeDifference(old.Strain[i], new.Strain[i])
+			if difference > rez {
+				rez = difference
 			}
 
 		}
-		return flag
+		return rez
 
 	}
 
-	difference = math.Abs((new.Moment - old.Moment) / new.Moment * 100)
-	if difference > accuracy {
-		flag = false
-	}
+	return relativeDifference(old.Moment, new.Moment)
+}
 
-	return flag
+// relativeDifference относительное изменение величины в %.
+func relativeDifference(old, new float64) float64 {
+	rez := math.Abs((new - old) / new * 100)
+	return rez
 }
 
-// Calculate считает всё и добавляет данные в Rigid и Flex и выдаёт карты результатов
-func Calculate(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex) (map[int]map[int]Approx, map[int]Rezult) {
+// Calculate считает всё и добавляет данные в Rigid и Flex и выдаёт приближения по порядку.
+// Сечение собирается полностью: при симметрии связи отражаются на другой борт,
+// у несимметричного сечения напряжения в пластинах считаются с учётом поворота нейтральной оси.
+// Приближения отражённых на другой борт пластин записываются с ключом минус номер пластины.
+// Суммы считаются в порядке номеров связей, поэтому одинаковые данные дают одинаковый до бита результат.
+// Приближения считаются до достижения точности без ограничения их числа,
+// ограничение и признак сходимости даёт CalculateWithOptions.
+func Calculate(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex) Iterations {
 
 	// TODO: написать тесты
 
-	approxData := make(map[int]map[int]Approx)
-	rezultData := make(map[int]Rezult)
+	return calculateMembers(baseData, buildMembers(rigid, flex, baseData.Symmetry))
+
+}
+
+// CalculateWithOptions считает как Calculate с заданными параметрами итераций
+// и возвращает сведения о сходимости, ошибка возвращается при недопустимых параметрах.
+func CalculateWithOptions(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex, opts *Options) (Iterations, Convergence, error) {
+	return iterateMembers(context.Background(), baseData, buildMembers(rigid, flex, baseData.Symmetry), opts)
+}
 
-	area := calcSumRigidArea(rigid) + calcSumFlexArea(flex)
-	staticMoment := calcSumRigidStaticMoment(rigid) + calcSumFlexStaticMoment(flex)
-	momentOfInertia := calcSumRigidMomentOfInertia(rigid) + calcSumFlexMomentOfInertia(flex)
+// CalculateContext считает как CalculateWithOptions с возможностью отмены.
+// Отмена проверяется между приближениями и между пластинами внутри приближения,
+// при отмене возвращаются уже посчитанные приближения и ошибка ctx.
+// Если задан opts.Observer, он получает каждое приближение по мере расчёта.
+func CalculateContext(ctx context.Context, baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex, opts *Options) (Iterations, Convergence, error) {
+	return iterateMembers(ctx, baseData, buildMembers(rigid, flex, baseData.Symmetry), opts)
+}
+
+// calculateMembers считает приближения по связям полного сечения.
+func calculateMembers(baseData *BaseData, members []member) Iterations {
+	rez, _, _ := iterateMembers(context.Background(), baseData, members, &Options{MaxIterations: -1})
+	return rez
+}
+
+// calculateLimited считает сечение не более чем за DefaultMaxIterations приближений
+// и возвращает последнее приближение и признак сходимости, используется при переборе вариантов сечения.
+func calculateLimited(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex) (Rezult, bool) {
+	rez, conv, _ := CalculateWithOptions(baseData, rigid, flex, &Options{})
+	return rez.Last(), conv.Converged
+}
+
+// iterateMembers считает приближения по связям полного сечения с ускорением сходимости.
+func iterateMembers(ctx context.Context, baseData *BaseData, members []member, opts *Options) (Iterations, Convergence, error) {
+	if err := opts.validate(); err != nil {
+		return nil, Convergence{Acceleration: opts.Acceleration}, err
+	}
+	gross := calcMemberSums(members)
+	breadths := controlBreadths(members)
+
+	maxIterations := opts.MaxIterations
+	if maxIterations == 0 {
+		maxIterations = DefaultMaxIterations
+	}
+	acc := accelerator{opts: *opts}
+	conv := Convergence{Acceleration: opts.Acceleration}
 
 	// Считаем первое приближение
-	rezultData[1] = createRezult(area, staticMoment, momentOfInertia, 0, 0, 0, baseData.Height, baseData.Strain, baseData.Symmetry, baseData.Moment)
+	var (
+		sec  section
+		loss sectionSums
+	)
+	first := Iteration{ID: 1}
+	first.Rezult, sec = createRezult(gross, loss, breadths, baseData)
+	rez := Iterations{first}
+	opts.observe(&rez[0])
 
 	// Расчёт 2 и последующих приближений
 
-	for id := 2; ; id++ {
+	for id := 2; maxIterations < 0 || id <= maxIterations; id++ {
+		if err := ctx.Err(); err != nil {
+			conv.Iterations = len(rez)
+			return rez, conv, err
+		}
+		old := &rez[len(rez)-1]
 
 		// С определением момента можно как-то лучше, но пока не пойму как
 		var (
@@ -89,30 +191,36 @@ func Calculate(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex) (map[
 		)
 
 		if baseData.Moment == 0 {
-			moment = rezultData[id-1].Moment
+			moment = old.Rezult.Moment
 		} else {
 			moment = baseData.Moment
 		}
 
-		approxData[id] = createAllApprox(&flex, moment, rezultData[id-1].CenterOfMass, rezultData[id-1].MomentOfInertia, baseData.ElasticModul, baseData.MomentFlag)
+		approx, err := createAllApprox(ctx, members, &sec, moment, baseData.ElasticModul, baseData.MomentFlag, opts.Workers)
+		if err != nil {
+			conv.Iterations = len(rez)
+			return rez, conv, err
+		}
 
-		areaLoss := calcSumApproxArea(approxData[id])
-		staticMomentLoss := calcSumApproxStaticMoment(approxData[id])
-		momentOfInertiaLoss := calcSumApproxMomentOfInertia(approxData[id])
+		loss = acc.next(loss, calcApproxLoss(approx))
 
-		rezultData[id] = createRezult(area, staticMoment, momentOfInertia,
-			areaLoss, staticMomentLoss, momentOfInertiaLoss,
-			baseData.Height, baseData.Strain, baseData.Symmetry, baseData.Moment)
+		new := Iteration{ID: id, Approx: approx}
+		new.Rezult, sec = createRezult(gross, loss, breadths, baseData)
+		rez = append(rez, new)
+		old = &rez[len(rez)-2]
+		opts.observe(&rez[len(rez)-1])
 
 		// Сравнение нового и старого результата для выхода цикла
-		old := rezultData[id-1]
-		new := rezultData[id]
-		if accuracyCheck(&old, &new, baseData.Accuracy, baseData.Moment) {
+		step := createTraceStep(old, &new, baseData.Moment)
+		conv.Trace = append(conv.Trace, step)
+		if accuracyCheck(&old.Rezult, &new.Rezult, baseData.Accuracy, baseData.Moment) {
+			conv.Converged = true
 			break
 		}
 
 	}
+	conv.Iterations = len(rez)
 
-	return approxData, rezultData
+	return rez, conv, nil
 
 }
@@ -0,0 +1,115 @@
+package strength
+
+import (
+	"fmt"
+)
+
+// DamageBox район повреждения в поперечном сечении.
+// Связь считается разрушенной, если её центр тяжести попадает в район.
+type DamageBox struct {
+	HeightMin, HeightMax           float64 // границы по высоте относительно ОП м
+	HalfBreadthMin, HalfBreadthMax float64 // границы по полушироте относительно ДП м, правый борт положительный
+}
+
+// Damage сценарий повреждения корпуса.
+type Damage struct {
+	Boxes    []DamageBox            // районы повреждения
+	Elements []ElementRef           // полностью разрушенные связи (на обоих бортах при симметрии)
+	Residual map[ElementRef]float64 // доля сохранившейся площади частично разрушенных связей от 0 до 1
+}
+
+// contains проверяет попадание точки в район повреждения.
+func (b *DamageBox) contains(height, halfBreadth float64) bool {
+	return height >= b.HeightMin && height <= b.HeightMax &&
+		halfBreadth >= b.HalfBreadthMin && halfBreadth <= b.HalfBreadthMax
+}
+
+// CollisionDamage район повреждения при столкновении по IACS CSR (Pt 1, Ch 5, Sec 3):
+// высота 0.75D от палубы у борта вниз, глубина проникновения B/16 от борта.
+// depth высота борта D м, breadth ширина судна B м, starboard повреждён правый борт.
+func CollisionDamage(depth, breadth float64, starboard bool) DamageBox {
+	box := DamageBox{
+		HeightMin:      depth - 0.75*depth,
+		HeightMax:      depth,
+		HalfBreadthMin: breadth/2 - breadth/16,
+		HalfBreadthMax: breadth / 2,
+	}
+	if !starboard {
+		box.HalfBreadthMin, box.HalfBreadthMax = -box.HalfBreadthMax, -box.HalfBreadthMin
+	}
+	return box
+}
+
+// GroundingDamage район повреждения при посадке на мель по IACS CSR (Pt 1, Ch 5, Sec 3):
+// высота B/20 от ОП, ширина 0.6B.
+// center полуширота середины района м, для повреждения по ДП 0.
+func GroundingDamage(breadth, center float64) DamageBox {
+	return DamageBox{
+		HeightMin:      0,
+		HeightMax:      breadth / 20,
+		HalfBreadthMin: center - 0.3*breadth,
+		HalfBreadthMax: center + 0.3*breadth,
+	}
+}
+
+// residual возвращает долю сохранившейся площади связи.
+func (d *Damage) residual(m *member) float64 {
+	for _, ref := range d.Elements {
+		if ref == m.ref {
+			return 0
+		}
+	}
+	for _, box := range d.Boxes {
+		if box.contains(m.height, m.halfBreadth) {
+			return 0
+		}
+	}
+	if val, ok := d.Residual[m.ref]; ok {
+		return val
+	}
+	return 1
+}
+
+// applyDamage убирает из сечения разрушенные связи и уменьшает частично разрушенные.
+func (d *Damage) applyDamage(members []member) ([]member, error) {
+	for ref, val := range d.Residual {
+		if val < 0 || val > 1 {
+			return nil, fmt.Errorf("bad residual %v of element %+v", val, ref)
+		}
+	}
+	rez := make([]member, 0, len(members))
+	for _, m := range members {
+		k := d.residual(&m)
+		if k == 0 {
+			continue
+		}
+		m.area *= k
+		if m.flex != nil && k != 1 {
+			// у частично разрушенной связи уменьшается число работающих пластин,
+			// размеры пластины и её устойчивость не меняются
+			f := *m.flex
+			f.Count *= k
+			f.AreaEnd *= k
+			m.flex = &f
+		}
+		rez = append(rez, m)
+	}
+	if len(rez) == 0 {
+		return nil, fmt.Errorf("whole section is damaged")
+	}
+	return rez, nil
+}
+
+// CalculateDamaged считает остаточную прочность повреждённого сечения.
+// Связи должны быть предварительно посчитаны CalcAllRigid и CalcAllFlex,
+// при симметрии связи задают правый борт и отражаются на левый, после чего повреждение
+// накладывается на полное сечение. У несимметрично повреждённого сечения нейтральная ось
+// поворачивается, и напряжения в пластинах считаются по косому изгибу.
+// Приближения отражённых на левый борт пластин записываются с ключом минус номер пластины.
+func CalculateDamaged(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex, damage *Damage) (Iterations, error) {
+	members, err := damage.applyDamage(buildMembers(rigid, flex, baseData.Symmetry))
+	if err != nil {
+		return nil, err
+	}
+	return calculateMembers(baseData, members), nil
+}
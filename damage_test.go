@@ -0,0 +1,59 @@
+package strength
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCollisionDamage(t *testing.T) {
+	got := CollisionDamage(20, 32, false)
+	want := DamageBox{HeightMin: 5, HeightMax: 20, HalfBreadthMin: -16, HalfBreadthMax: -14}
+	if got != want {
+		t.Errorf("CollisionDamage() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCalculateDamaged(t *testing.T) {
+	base := BaseData{Height: []float64{0, 10}, Strain: []float64{23.5, 23.5}, Symmetry: true, Accuracy: 0.1}
+	rigid := map[int]Rigid{
+		1: {ID: 1, AreaStart: 30, Height: 0.1, HalfBreadth: 4, Count: 10},
+		2: {ID: 2, AreaStart: 30, Height: 9.9, HalfBreadth: 4, Count: 10},
+	}
+	flex := map[int]Flex{
+		1: {ID: 1, Length: 240, Width: 60, ThicknessStart: 12, HalfBreadth: 4, Count: 10},
+		2: {ID: 2, Length: 240, Width: 60, ThicknessStart: 10, Height: 10, HalfBreadth: 4, Count: 10},
+		3: {ID: 3, Length: 60, Width: 240, ThicknessStart: 10, Height: 5, HalfBreadth: 8, Count: 4},
+	}
+	CalcAllRigid(rigid, base.Age)
+	CalcAllFlex(flex, base.Age)
+
+	intact := Calculate(&base, rigid, flex).Last()
+	rezult, err := CalculateDamaged(&base, rigid, flex, &Damage{})
+	if err != nil {
+		t.Fatalf("CalculateDamaged() error = %v", err)
+	}
+	got := rezult.Last()
+	if math.Abs(got.Moment-intact.Moment)/intact.Moment > 1e-9 {
+		t.Errorf("CalculateDamaged() intact moment = %v, want %v", got.Moment, intact.Moment)
+	}
+	if got.ProductOfInertia != 0 || got.NeutralAxisAngle != 0 {
+		t.Errorf("CalculateDamaged() intact section is not symmetric %+v", got)
+	}
+
+	rezult, err = CalculateDamaged(&base, rigid, flex, &Damage{Boxes: []DamageBox{CollisionDamage(10, 16, true)}})
+	if err != nil {
+		t.Fatalf("CalculateDamaged() error = %v", err)
+	}
+	got = rezult.Last()
+	if got.Moment >= intact.Moment {
+		t.Errorf("CalculateDamaged() damaged moment %v not less than intact %v", got.Moment, intact.Moment)
+	}
+	if got.HalfBreadth >= 0 || got.NeutralAxisAngle == 0 {
+		t.Errorf("CalculateDamaged() neutral axis not shifted to intact side %+v", got)
+	}
+
+	_, err = CalculateDamaged(&base, rigid, flex, &Damage{Boxes: []DamageBox{{HeightMax: 20, HalfBreadthMin: -20, HalfBreadthMax: 20}}})
+	if err == nil {
+		t.Errorf("CalculateDamaged() whole section damaged error expected")
+	}
+}
@@ -0,0 +1,97 @@
+package strength
+
+import (
+	"fmt"
+)
+
+// Support условие закрепления корпуса при расчёте прогиба.
+type Support int
+
+const (
+	// SupportEnds прогиб отсчитывается от прямой через концевые станции.
+	SupportEnds Support = iota
+	// SupportAft заделка на первой (кормовой) станции.
+	SupportAft
+	// SupportFore заделка на последней (носовой) станции.
+	SupportFore
+)
+
+// Station расчётная станция по длине корпуса.
+type Station struct {
+	X               float64 // абсцисса станции м
+	Moment          float64 // изгибающий момент кН*м, перегиб положительный
+	MomentOfInertia float64 // момент инерции сечения см2*м2 (Rezult.MomentOfInertia)
+}
+
+// Deflection кривизна, угол поворота и прогиб корпуса по станциям.
+// Прогиб положительный вверх, при перегибе середина корпуса поднимается над концами.
+type Deflection struct {
+	X          []float64 // абсциссы станций м
+	Curvature  []float64 // кривизна 1/м
+	Slope      []float64 // угол поворота рад
+	Deflection []float64 // прогиб м
+}
+
+// calcCurvature считает кривизну оси корпуса.
+// Момент инерции переводится из см2*м2 в м4, модуль упругости в кПа.
+func calcCurvature(moment, momentOfInertia, elasticModul float64) float64 {
+	rez := -moment / (elasticModul * momentOfInertia / 10000)
+	return rez
+}
+
+// CalcDeflection интегрирует кривизну по длине корпуса и находит углы поворота и прогибы.
+// Кривизна между станциями считается линейной, интегрирование точное для такой кривизны.
+// elasticModul модуль упругости кПа, станции должны идти по возрастанию абсциссы.
+func CalcDeflection(stations []Station, elasticModul float64, support Support) (*Deflection, error) {
+	n := len(stations)
+	if n < 2 {
+		return nil, fmt.Errorf("need at least 2 stations")
+	}
+	if elasticModul <= 0 {
+		return nil, fmt.Errorf("bad elastic modulus %v", elasticModul)
+	}
+
+	rez := Deflection{
+		X:          make([]float64, n),
+		Curvature:  make([]float64, n),
+		Slope:      make([]float64, n),
+		Deflection: make([]float64, n),
+	}
+	for key, val := range stations {
+		if val.MomentOfInertia <= 0 {
+			return nil, fmt.Errorf("bad moment of inertia %v at station %d", val.MomentOfInertia, key)
+		}
+		if key != 0 && val.X <= stations[key-1].X {
+			return nil, fmt.Errorf("stations not ordered at %d", key)
+		}
+		rez.X[key] = val.X
+		rez.Curvature[key] = calcCurvature(val.Moment, val.MomentOfInertia, elasticModul)
+	}
+
+	// интегрирование с нулевыми углом поворота и прогибом на первой станции
+	for i := 1; i < n; i++ {
+		h := rez.X[i] - rez.X[i-1]
+		k0, k1 := rez.Curvature[i-1], rez.Curvature[i]
+		rez.Slope[i] = rez.Slope[i-1] + h*(k0+k1)/2
+		rez.Deflection[i] = rez.Deflection[i-1] + rez.Slope[i-1]*h + h*h*(2*k0+k1)/6
+	}
+
+	// добавка перемещения тела как жёсткого целого по условиям закрепления w += a + b*(x-x0)
+	var a, b float64
+	x0 := rez.X[0]
+	switch support {
+	case SupportEnds:
+		b = -rez.Deflection[n-1] / (rez.X[n-1] - x0)
+	case SupportAft:
+	case SupportFore:
+		b = -rez.Slope[n-1]
+		a = -rez.Deflection[n-1] - b*(rez.X[n-1]-x0)
+	default:
+		return nil, fmt.Errorf("unknown support %d", support)
+	}
+	for key := range rez.X {
+		rez.Slope[key] += b
+		rez.Deflection[key] += a + b*(rez.X[key]-x0)
+	}
+	return &rez, nil
+}
@@ -0,0 +1,70 @@
+package strength
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalcDeflection(t *testing.T) {
+	stations := make([]Station, 11)
+	for key := range stations {
+		stations[key] = Station{X: float64(key) * 10, Moment: 1000, MomentOfInertia: 10000}
+	}
+	tests := []struct {
+		name     string
+		support  Support
+		key      int
+		want     float64
+		wantErr  bool
+		stations []Station
+	}{
+		{
+			name:     "ends, midship",
+			support:  SupportEnds,
+			key:      5,
+			want:     0.00625,
+			stations: stations,
+		},
+		{
+			name:     "aft clamped, fore end",
+			support:  SupportAft,
+			key:      10,
+			want:     -0.025,
+			stations: stations,
+		},
+		{
+			name:     "fore clamped, aft end",
+			support:  SupportFore,
+			key:      0,
+			want:     -0.025,
+			stations: stations,
+		},
+		{
+			name:     "single station",
+			support:  SupportEnds,
+			wantErr:  true,
+			stations: stations[:1],
+		},
+		{
+			name:     "unknown support",
+			support:  Support(10),
+			wantErr:  true,
+			stations: stations,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CalcDeflection(tt.stations, 2e8, tt.support)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CalcDeflection() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if math.Abs(got.Deflection[tt.key]-tt.want) > 1e-12 {
+				t.Errorf("CalcDeflection() deflection = %v, want %v", got.Deflection[tt.key], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+)
+
+// SNCurve двухзвенная кривая усталости log N = log a - m log Δσ, размах напряжений в МПа.
+type SNCurve struct {
+	Name   string  // обозначение кривой
+	M1     float64 // показатель первого участка
+	LogA1  float64 // log a первого участка
+	M2     float64 // показатель второго участка
+	LogA2  float64 // log a второго участка
+	Switch float64 // число циклов перелома кривой
+}
+
+// Кривые усталости для конструкций на воздухе по DNV-RP-C203.
+var (
+	SNCurveD  = SNCurve{Name: "D", M1: 3, LogA1: 12.164, M2: 5, LogA2: 15.606, Switch: 1e7}
+	SNCurveE  = SNCurve{Name: "E", M1: 3, LogA1: 12.010, M2: 5, LogA2: 15.350, Switch: 1e7}
+	SNCurveF  = SNCurve{Name: "F", M1: 3, LogA1: 11.855, M2: 5, LogA2: 15.091, Switch: 1e7}
+	SNCurveF1 = SNCurve{Name: "F1", M1: 3, LogA1: 11.699, M2: 5, LogA2: 14.832, Switch: 1e7}
+	SNCurveF3 = SNCurve{Name: "F3", M1: 3, LogA1: 11.546, M2: 5, LogA2: 14.576, Switch: 1e7}
+	SNCurveG  = SNCurve{Name: "G", M1: 3, LogA1: 11.398, M2: 5, LogA2: 14.330, Switch: 1e7}
+	SNCurveW1 = SNCurve{Name: "W1", M1: 3, LogA1: 11.261, M2: 5, LogA2: 14.101, Switch: 1e7}
+)
+
+// SNCurves кривые усталости по обозначениям.
+var SNCurves = map[string]SNCurve{
+	SNCurveD.Name:  SNCurveD,
+	SNCurveE.Name:  SNCurveE,
+	SNCurveF.Name:  SNCurveF,
+	SNCurveF1.Name: SNCurveF1,
+	SNCurveF3.Name: SNCurveF3,
+	SNCurveG.Name:  SNCurveG,
+	SNCurveW1.Name: SNCurveW1,
+}
+
+// Cycles число циклов до разрушения при размахе напряжений stressRange МПа.
+func (c *SNCurve) Cycles(stressRange float64) float64 {
+	if stressRange >= c.switchRange() {
+		return math.Pow(10, c.LogA1-c.M1*math.Log10(stressRange))
+	}
+	return math.Pow(10, c.LogA2-c.M2*math.Log10(stressRange))
+}
+
+// switchRange размах напряжений в точке перелома кривой МПа.
+func (c *SNCurve) switchRange() float64 {
+	return math.Pow(10, (c.LogA1-math.Log10(c.Switch))/c.M1)
+}
+
+// ConnectionType тип соединения продольной балки на поперечной переборке или рамной связи.
+type ConnectionType int
+
+const (
+	// ConnectionBracketBoth кницы с обеих сторон.
+	ConnectionBracketBoth ConnectionType = iota
+	// ConnectionBracketOne кница с одной стороны.
+	ConnectionBracketOne
+	// ConnectionWebStiffener без книц, с ребром жёсткости на стенке рамной связи.
+	ConnectionWebStiffener
+	// ConnectionPlain без книц и рёбер, приварка только к стенке.
+	ConnectionPlain
+)
+
+// SCF типовой коэффициент концентрации напряжений у носка кницы.
+// Значения ориентировочные, для конкретного узла задаются в FatigueDetail.SCF по правилам.
+func (c ConnectionType) SCF() float64 {
+	switch c {
+	case ConnectionBracketBoth:
+		return 1.13
+	case ConnectionBracketOne:
+		return 1.28
+	case ConnectionWebStiffener:
+		return 1.4
+	case ConnectionPlain:
+		return 1.6
+	}
+	return 1
+}
+
+// FatigueLoad долговременное распределение нагрузок за срок службы.
+// Размахи заданы при вероятности превышения 1/Cycles и распределены по Вейбуллу.
+type FatigueLoad struct {
+	MomentRange float64 // размах волнового изгибающего момента кН*м
+	Shape       float64 // параметр формы распределения Вейбулла
+	Cycles      float64 // число циклов нагрузки за срок службы
+	DesignLife  float64 // расчётный срок службы лет
+}
+
+// FatigueDetail узел крепления продольной балки, номер совпадает с номером жёсткой связи.
+type FatigueDetail struct {
+	Spacing        float64        // шпация продольных балок м
+	Span           float64        // пролёт балки м
+	SectionModulus float64        // момент сопротивления балки с присоединённым пояском см3
+	PressureRange  float64        // размах динамического давления кПа
+	Connection     ConnectionType // тип соединения
+	SCF            float64        // коэффициент концентрации, если 0 то по типу соединения
+	Curve          SNCurve        // кривая усталости
+}
+
+// Fatigue результат расчёта усталости одной связи.
+type Fatigue struct {
+	ID          int     // номер жёсткой связи
+	Name        string  // имя связи
+	GlobalRange float64 // размах напряжений от общего изгиба МПа
+	LocalRange  float64 // размах напряжений от изгиба балки давлением МПа
+	StressRange float64 // расчётный размах у носка кницы с учётом концентрации МПа
+	Damage      float64 // накопленная повреждённость по Пальмгрену-Майнеру за срок службы
+	Life        float64 // усталостная долговечность лет
+}
+
+// calcGlobalStressRange размах напряжений от общего изгиба на высоте height МПа.
+func calcGlobalStressRange(rezult *Rezult, momentRange, height float64) float64 {
+	rez := momentRange / momentOfResistance(rezult.MomentOfInertia, rezult.CenterOfMass, height) * 10
+	return rez
+}
+
+// calcLocalStressRange размах напряжений изгиба балки с заделанными концами на опоре МПа.
+func (d *FatigueDetail) calcLocalStressRange() float64 {
+	if d.SectionModulus == 0 {
+		return 0
+	}
+	rez := d.PressureRange * d.Spacing * math.Pow(d.Span, 2) * 1000 / (12 * d.SectionModulus)
+	return rez
+}
+
+// calcWeibullDamage считает повреждённость по двухзвенной кривой усталости при распределении
+// размахов напряжений по Вейбуллу с наибольшим размахом stressRange за cycles циклов.
+// Без размаха напряжений повреждений нет.
+func calcWeibullDamage(curve *SNCurve, stressRange, shape, cycles float64) float64 {
+	if stressRange <= 0 {
+		return 0
+	}
+	q := stressRange / math.Pow(math.Log(cycles), 1/shape)
+	x := math.Pow(curve.switchRange()/q, shape)
+	a1 := 1 + curve.M1/shape
+	a2 := 1 + curve.M2/shape
+	g1, _ := math.Lgamma(a1)
+	g2, _ := math.Lgamma(a2)
+	upper := math.Exp(g1) * (1 - regularizedGammaP(a1, x))
+	lower := math.Exp(g2) * regularizedGammaP(a2, x)
+	rez := cycles * (math.Pow(q, curve.M1)/math.Pow(10, curve.LogA1)*upper +
+		math.Pow(q, curve.M2)/math.Pow(10, curve.LogA2)*lower)
+	return rez
+}
+
+// regularizedGammaP регуляризованная нижняя неполная гамма-функция P(a, x).
+func regularizedGammaP(a, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if math.IsInf(x, 1) {
+		return 1
+	}
+	lg, _ := math.Lgamma(a)
+	if x < a+1 {
+		// ряд
+		sum := 1 / a
+		term := sum
+		for n := 1; n < 500; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return sum * math.Exp(-x+a*math.Log(x)-lg)
+	}
+	// цепная дробь для Q(a, x) по методу Лентца
+	tiny := 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < 500; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return 1 - math.Exp(-x+a*math.Log(x)-lg)*h
+}
+
+// CalcFatigue считает усталостную долговечность узлов крепления продольных балок.
+// Размах напряжений от общего изгиба берётся по моменту сопротивления сечения rezult на высоте балки,
+// к нему добавляется размах напряжений изгиба балки давлением, сумма умножается на коэффициент концентрации.
+// Повреждённость за срок службы считается по правилу Пальмгрена-Майнера для распределения Вейбулла.
+func CalcFatigue(rezult *Rezult, rigid map[int]Rigid, load *FatigueLoad, details map[int]FatigueDetail) (map[int]Fatigue, error) {
+	if load.Shape <= 0 || load.Cycles <= 1 || load.DesignLife <= 0 {
+		return nil, fmt.Errorf("bad fatigue load %+v", *load)
+	}
+	rez := make(map[int]Fatigue, len(details))
+	for id, d := range details {
+		r, ok := rigid[id]
+		if !ok {
+			return nil, fmt.Errorf("fatigue detail of missing rigid %d", id)
+		}
+		if d.Curve.M1 == 0 {
+			return nil, fmt.Errorf("no S-N curve for rigid %d", id)
+		}
+		scf := d.SCF
+		if scf == 0 {
+			scf = d.Connection.SCF()
+		}
+		f := Fatigue{
+			ID:          id,
+			Name:        r.Name,
+			GlobalRange: calcGlobalStressRange(rezult, load.MomentRange, r.Height),
+			LocalRange:  d.calcLocalStressRange(),
+		}
+		f.StressRange = scf * (f.GlobalRange + f.LocalRange)
+		f.Damage = calcWeibullDamage(&d.Curve, f.StressRange, load.Shape, load.Cycles)
+		f.Life = math.Inf(1)
+		if f.Damage > 0 {
+			f.Life = load.DesignLife / f.Damage
+		}
+		rez[id] = f
+	}
+	return rez, nil
+}
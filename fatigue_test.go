@@ -0,0 +1,95 @@
+package strength
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSNCurve_Cycles(t *testing.T) {
+	tests := []struct {
+		name        string
+		curve       SNCurve
+		stressRange float64
+		want        float64
+	}{
+		{
+			name:        "D first slope",
+			curve:       SNCurveD,
+			stressRange: 100,
+			want:        math.Pow(10, 6.164),
+		},
+		{
+			name:        "D second slope",
+			curve:       SNCurveD,
+			stressRange: 10,
+			want:        math.Pow(10, 10.606),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.curve.Cycles(tt.stressRange); math.Abs(got-tt.want)/tt.want > 1e-12 {
+				t.Errorf("SNCurve.Cycles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_calcWeibullDamage(t *testing.T) {
+	const (
+		shape  = 0.9
+		cycles = 1e8
+	)
+	curve := SNCurveF
+
+	// прямое суммирование повреждённости по плотности распределения Вейбулла
+	sum := func(stressRange float64) float64 {
+		q := stressRange / math.Pow(math.Log(cycles), 1/shape)
+		var rez float64
+		step := 0.01
+		for s := step / 2; s < 20*stressRange; s += step {
+			density := shape / q * math.Pow(s/q, shape-1) * math.Exp(-math.Pow(s/q, shape))
+			rez += cycles * density * step / curve.Cycles(s)
+		}
+		return rez
+	}
+
+	tests := []struct {
+		name        string
+		stressRange float64
+		want        float64
+	}{
+		{name: "weibull sum", stressRange: 150, want: sum(150)},
+		{name: "zero range", stressRange: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calcWeibullDamage(&curve, tt.stressRange, shape, cycles)
+			if tt.want == 0 {
+				if got != 0 {
+					t.Errorf("calcWeibullDamage() = %v, want 0", got)
+				}
+				return
+			}
+			if math.Abs(got-tt.want)/tt.want > 1e-3 {
+				t.Errorf("calcWeibullDamage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_regularizedGammaP(t *testing.T) {
+	tests := []struct {
+		a, x, want float64
+	}{
+		{1, 1, 1 - math.Exp(-1)},
+		{1, 5, 1 - math.Exp(-5)},
+		{2, 3, 1 - 4*math.Exp(-3)},
+		{0.5, 2, math.Erf(math.Sqrt(2))},
+		{2, math.Inf(1), 1},
+	}
+	for _, tt := range tests {
+		if got := regularizedGammaP(tt.a, tt.x); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("regularizedGammaP(%v, %v) = %v, want %v", tt.a, tt.x, got, tt.want)
+		}
+	}
+}
@@ -10,6 +10,8 @@ type Flex struct {
 	Corrosion       float64 // годовая коррозия мм/год
 	ThicknessEnd    float64 // толщина с учётом коррозии мм
 	Height          float64 // положение центра тяжести относительно ОП м
+	HalfBreadth     float64 // полуширота центра тяжести относительно ДП м, правый борт положительный
+	Side            Side    // борт на котором расположена связь
 	Count           float64 // колличество связей
 	AreaStart       float64 // площадь в начале срока службы см2
 	AreaEnd         float64 // площадь в конце срока службы с учётом колличества связей см2
@@ -27,37 +29,28 @@ func (f *Flex) calc(age float64) {
 	f.MomentOfInertia = calcMomentOfInertia(f.AreaEnd, f.Height)
 }
 
-// CalcAllFlex просчитать все гибкие связи.
-func CalcAllFlex(data map[int]Flex, age float64) {
-	for key, flex := range data {
-		flex.calc(age)
-		data[key] = flex
-	}
-}
-
-// calcSumFlexArea рассчитывает суммарную площадь всех переданных связей.
-func calcSumFlexArea(data map[int]Flex) float64 {
-	var sum float64
-	for _, val := range data {
-		sum += val.AreaEnd
-	}
-	return sum
+// restore восстанавливает связь до построечной толщины.
+func (f *Flex) restore() {
+	f.AreaStart = (f.ThicknessStart / 10) * f.Width
+	f.ThicknessEnd = f.ThicknessStart
+	f.AreaEnd = f.AreaStart * f.Count
+	f.StaticMoment = calcStaticMoment(f.AreaEnd, f.Height)
+	f.MomentOfInertia = calcMomentOfInertia(f.AreaEnd, f.Height)
 }
 
-// calcSumFlexStaticMoment рассчитывает суммарный статический момент всех переданных связей.
-func calcSumFlexStaticMoment(data map[int]Flex) float64 {
-	var sum float64
-	for _, val := range data {
-		sum += val.StaticMoment
+// copyFlex копирует карту гибких связей.
+func copyFlex(data map[int]Flex) map[int]Flex {
+	rez := make(map[int]Flex, len(data))
+	for key, val := range data {
+		rez[key] = val
 	}
-	return sum
+	return rez
 }
 
-// calcSumFlexMomentOfInertia рассчитывает суммарный момент инерции всех переданных связей.
-func calcSumFlexMomentOfInertia(data map[int]Flex) float64 {
-	var sum float64
-	for _, val := range data {
-		sum += val.MomentOfInertia
+// CalcAllFlex просчитать все гибкие связи.
+func CalcAllFlex(data map[int]Flex, age float64) {
+	for key, flex := range data {
+		flex.calc(age)
+		data[key] = flex
 	}
-	return sum
 }
@@ -0,0 +1,395 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// formTolerance точность сходимости индекса надёжности и расчётной точки.
+	formTolerance = 1e-3
+	// formMaxIterations наибольшее число итераций поиска расчётной точки.
+	formMaxIterations = 50
+	// formStep шаг численного дифференцирования в стандартном нормальном пространстве.
+	formStep = 0.05
+	// formAccuracy наибольшая точность приближений в % при расчётах надёжности,
+	// грубее нельзя, иначе численные производные превращаются в шум.
+	formAccuracy = 1e-4
+)
+
+// DesignPoint координата расчётной точки по одной случайной величине.
+type DesignPoint struct {
+	Name       string  // имя случайной величины
+	Value      float64 // значение в расчётной точке
+	U          float64 // значение в стандартном нормальном пространстве
+	Importance float64 // коэффициент важности alpha^2
+}
+
+// FORM результаты расчёта надёжности методами первого и второго порядка.
+type FORM struct {
+	ReliabilityIndex       float64       // индекс надёжности первого порядка
+	FailureProbability     float64       // вероятность отказа первого порядка
+	DesignPoint            []DesignPoint // расчётная точка и коэффициенты важности
+	Iterations             int           // число итераций поиска расчётной точки
+	Curvatures             []float64     // главные кривизны поверхности отказа, если считался SORM
+	SORMFailureProbability float64       // вероятность отказа второго порядка (формула Брейтунга)
+	SORMReliabilityIndex   float64       // обобщённый индекс надёжности второго порядка
+}
+
+// randomVariable случайная величина функции предельного состояния.
+type randomVariable struct {
+	name string
+	dist Distribution
+	set  func(s *mcSample, val float64)
+}
+
+// limitState функция предельного состояния: предельный момент минус действующий.
+type limitState struct {
+	baseData *BaseData
+	rigid    map[int]Rigid
+	flex     map[int]Flex
+	base     mcSample
+	vars     []randomVariable
+}
+
+// newLimitState собирает случайные величины модели, неслучайные величины фиксируются.
+func newLimitState(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex, model *ReliabilityModel) *limitState {
+	data := *baseData
+	data.Accuracy = math.Min(data.Accuracy, formAccuracy)
+	ls := limitState{
+		baseData: &data,
+		rigid:    rigid,
+		flex:     flex,
+		base:     newSample(len(rigid), len(flex)),
+	}
+	add := func(name string, d Distribution, set func(s *mcSample, val float64)) {
+		if d.Kind == Deterministic {
+			set(&ls.base, d.Mean)
+			return
+		}
+		ls.vars = append(ls.vars, randomVariable{name: name, dist: d, set: set})
+	}
+
+	for _, id := range RigidIDs(rigid) {
+		id := id
+		add(fmt.Sprintf("площадь жёсткой связи %d", id), defaultMultiplier(model.Area[id]),
+			func(s *mcSample, val float64) { s.area[id] = val })
+		add(fmt.Sprintf("коррозия жёсткой связи %d", id), defaultMultiplier(model.RigidCorrosion[id]),
+			func(s *mcSample, val float64) { s.rigidCorrosion[id] = val })
+	}
+	for _, id := range FlexIDs(flex) {
+		id := id
+		add(fmt.Sprintf("толщина гибкой связи %d", id), defaultMultiplier(model.Thickness[id]),
+			func(s *mcSample, val float64) { s.thickness[id] = val })
+		add(fmt.Sprintf("коррозия гибкой связи %d", id), defaultMultiplier(model.FlexCorrosion[id]),
+			func(s *mcSample, val float64) { s.flexCorrosion[id] = val })
+	}
+	add("предел текучести", defaultMultiplier(model.Yield), func(s *mcSample, val float64) { s.yield = val })
+	add("момент на тихой воде", model.StillWater, func(s *mcSample, val float64) { s.load += val })
+	add("волновой момент", model.Wave, func(s *mcSample, val float64) { s.load += val })
+	return &ls
+}
+
+// toX переводит точку из стандартного нормального пространства в исходные величины.
+func (ls *limitState) toX(u []float64) []float64 {
+	x := make([]float64, len(u))
+	for key, val := range u {
+		x[key] = ls.vars[key].dist.FromNormal(val)
+	}
+	return x
+}
+
+// eval считает функцию предельного состояния в стандартном нормальном пространстве.
+func (ls *limitState) eval(u []float64) float64 {
+	s := ls.base.clone()
+	for key, val := range ls.toX(u) {
+		ls.vars[key].set(&s, val)
+	}
+	capacity := sampleCapacity(ls.baseData, ls.rigid, ls.flex, &s)
+	return capacity - s.load
+}
+
+// limitFunc функция предельного состояния в стандартном нормальном пространстве.
+type limitFunc func(u []float64) float64
+
+// gradient считает градиент функции предельного состояния центральными разностями.
+func gradient(f limitFunc, u []float64) []float64 {
+	rez := make([]float64, len(u))
+	point := append([]float64(nil), u...)
+	for key := range u {
+		point[key] = u[key] + formStep
+		plus := f(point)
+		point[key] = u[key] - formStep
+		minus := f(point)
+		point[key] = u[key]
+		rez[key] = (plus - minus) / (2 * formStep)
+	}
+	return rez
+}
+
+// hessian считает матрицу вторых производных функции предельного состояния.
+func hessian(f limitFunc, u []float64, g0 float64) [][]float64 {
+	n := len(u)
+	rez := make([][]float64, n)
+	for i := range rez {
+		rez[i] = make([]float64, n)
+	}
+	point := append([]float64(nil), u...)
+	h := formStep
+	for i := 0; i < n; i++ {
+		point[i] = u[i] + h
+		plus := f(point)
+		point[i] = u[i] - h
+		minus := f(point)
+		point[i] = u[i]
+		rez[i][i] = (plus - 2*g0 + minus) / (h * h)
+		for j := i + 1; j < n; j++ {
+			var v [4]float64
+			for k, sign := range [4][2]float64{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}} {
+				point[i] = u[i] + sign[0]*h
+				point[j] = u[j] + sign[1]*h
+				v[k] = f(point)
+			}
+			point[i] = u[i]
+			point[j] = u[j]
+			rez[i][j] = (v[0] - v[1] - v[2] + v[3]) / (4 * h * h)
+			rez[j][i] = rez[i][j]
+		}
+	}
+	return rez
+}
+
+// norm евклидова норма вектора.
+func norm(v []float64) float64 {
+	var sum float64
+	for _, val := range v {
+		sum += val * val
+	}
+	return math.Sqrt(sum)
+}
+
+// RunFORM считает индекс надёжности методом первого порядка (алгоритм Хасофера-Линда-Рэкуица-Файслера)
+// по функции предельного состояния: предельный момент по Calculate минус сумма моментов на тихой воде и волнении.
+// Случайные величины и их распределения берутся из модели, величины считаются независимыми.
+// Если sorm, то вероятность отказа уточняется по главным кривизнам поверхности отказа в расчётной точке.
+func RunFORM(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex, model *ReliabilityModel, sorm bool) (*FORM, error) {
+	ls := newLimitState(baseData, rigid, flex, model)
+	n := len(ls.vars)
+	if n == 0 {
+		return nil, fmt.Errorf("no random variables")
+	}
+	rez, err := solveFORM(ls.eval, n, sorm)
+	if err != nil {
+		return nil, err
+	}
+	u := make([]float64, n)
+	for key, val := range rez.DesignPoint {
+		u[key] = val.U
+	}
+	for key, val := range ls.toX(u) {
+		rez.DesignPoint[key].Name = ls.vars[key].name
+		rez.DesignPoint[key].Value = val
+	}
+	return rez, nil
+}
+
+// solveFORM ищет расчётную точку функции предельного состояния n стандартных нормальных величин
+// и заполняет в ней U и коэффициенты важности, имена и значения исходных величин заполняет RunFORM.
+func solveFORM(f limitFunc, n int, sorm bool) (*FORM, error) {
+	u := make([]float64, n)
+	var (
+		g    float64
+		grad []float64
+		beta float64
+		rez  FORM
+	)
+	g0 := f(u)
+	for rez.Iterations = 1; ; rez.Iterations++ {
+		if rez.Iterations > formMaxIterations {
+			return nil, fmt.Errorf("design point not found in %d iterations", formMaxIterations)
+		}
+		g = f(u)
+		grad = gradient(f, u)
+		gradNorm := norm(grad)
+		if gradNorm == 0 {
+			return nil, fmt.Errorf("zero gradient of limit state")
+		}
+
+		// новая точка по HL-RF
+		var dot float64
+		for key := range u {
+			dot += grad[key] * u[key]
+		}
+		next := make([]float64, n)
+		for key := range u {
+			next[key] = (dot - g) / (gradNorm * gradNorm) * grad[key]
+		}
+
+		newBeta := norm(next)
+		var shift float64
+		for key := range u {
+			shift = math.Max(shift, math.Abs(next[key]-u[key]))
+		}
+		u = next
+		converged := math.Abs(newBeta-beta) < formTolerance && shift < formTolerance
+		beta = newBeta
+		if converged {
+			g = f(u)
+			grad = gradient(f, u)
+			break
+		}
+	}
+
+	// если в средней точке уже отказ, индекс надёжности отрицательный
+	if g0 < 0 {
+		beta = -beta
+	}
+	rez.ReliabilityIndex = beta
+	rez.FailureProbability = normalCDF(-beta)
+
+	gradNorm := norm(grad)
+	rez.DesignPoint = make([]DesignPoint, n)
+	for key := range u {
+		alpha := -grad[key] / gradNorm
+		rez.DesignPoint[key] = DesignPoint{
+			U:          u[key],
+			Importance: alpha * alpha,
+		}
+	}
+
+	if !sorm || n == 1 {
+		rez.SORMFailureProbability = rez.FailureProbability
+		rez.SORMReliabilityIndex = rez.ReliabilityIndex
+		return &rez, nil
+	}
+
+	alpha := make([]float64, n)
+	for key := range grad {
+		alpha[key] = -grad[key] / gradNorm
+	}
+	curvatures := calcCurvatures(hessian(f, u, g), alpha, gradNorm)
+	rez.Curvatures = curvatures
+	pf := rez.FailureProbability
+	for _, k := range curvatures {
+		factor := 1 + beta*k
+		if factor <= 0 {
+			return nil, fmt.Errorf("breitung formula not applicable, curvature %v", k)
+		}
+		pf /= math.Sqrt(factor)
+	}
+	rez.SORMFailureProbability = pf
+	rez.SORMReliabilityIndex = -normalInverse(pf)
+	return &rez, nil
+}
+
+// calcCurvatures считает главные кривизны поверхности отказа в расчётной точке.
+// Матрица вторых производных поворачивается так, чтобы последняя ось совпала с alpha,
+// кривизны это собственные значения её главного минора порядка n-1, делённые на норму градиента.
+func calcCurvatures(hess [][]float64, alpha []float64, gradNorm float64) []float64 {
+	n := len(alpha)
+	rot := rotationMatrix(alpha)
+
+	// A = R H R^T
+	a := make([][]float64, n)
+	for i := range a {
+		a[i] = make([]float64, n)
+		for j := range a[i] {
+			var sum float64
+			for k := 0; k < n; k++ {
+				for l := 0; l < n; l++ {
+					sum += rot[i][k] * hess[k][l] * rot[j][l]
+				}
+			}
+			a[i][j] = sum / gradNorm
+		}
+	}
+
+	minor := make([][]float64, n-1)
+	for i := range minor {
+		minor[i] = append([]float64(nil), a[i][:n-1]...)
+	}
+	rez := symmetricEigenvalues(minor)
+	sort.Float64s(rez)
+	return rez
+}
+
+// rotationMatrix строит ортогональную матрицу, последняя строка которой равна единичному вектору alpha.
+func rotationMatrix(alpha []float64) [][]float64 {
+	n := len(alpha)
+	rows := make([][]float64, 0, n)
+	rows = append(rows, append([]float64(nil), alpha...))
+	// ортогонализация Грама-Шмидта начиная с alpha по базисным векторам
+	for e := 0; e < n && len(rows) < n; e++ {
+		v := make([]float64, n)
+		v[e] = 1
+		for _, r := range rows {
+			var dot float64
+			for k := range v {
+				dot += v[k] * r[k]
+			}
+			for k := range v {
+				v[k] -= dot * r[k]
+			}
+		}
+		l := norm(v)
+		if l < 1e-8 {
+			continue
+		}
+		for k := range v {
+			v[k] /= l
+		}
+		rows = append(rows, v)
+	}
+	// alpha в конец
+	rez := append(rows[1:], rows[0])
+	return rez
+}
+
+// symmetricEigenvalues находит собственные значения симметричной матрицы методом Якоби.
+func symmetricEigenvalues(m [][]float64) []float64 {
+	n := len(m)
+	a := make([][]float64, n)
+	for i := range m {
+		a[i] = append([]float64(nil), m[i]...)
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		var off float64
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off < 1e-22 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					akp := a[k][p]
+					akq := a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk := a[p][k]
+					aqk := a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+			}
+		}
+	}
+	rez := make([]float64, n)
+	for i := range rez {
+		rez[i] = a[i][i]
+	}
+	return rez
+}
@@ -0,0 +1,181 @@
+package strength
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRunFORM(t *testing.T) {
+	// Предельный момент сечения из жёстких связей пропорционален пределу текучести,
+	// поэтому функция предельного состояния g = 94000·Y - Ls - Lw линейна по нормальным величинам
+	// и индекс надёжности точно равен μg/σg.
+	base, rigid, flex := reliabilityModel()
+	model := ReliabilityModel{
+		Yield:      Distribution{Kind: Normal, Mean: 1, StdDev: 0.1},
+		StillWater: Distribution{Kind: Normal, Mean: 30000, StdDev: 5000},
+		Wave:       Distribution{Kind: Normal, Mean: 20000, StdDev: 8000},
+	}
+	sigma := []float64{94000 * 0.1, 5000, 8000}
+	sigmaG := math.Sqrt(sigma[0]*sigma[0] + sigma[1]*sigma[1] + sigma[2]*sigma[2])
+	wantBeta := (94000 - 30000 - 20000) / sigmaG
+
+	for _, sorm := range []bool{false, true} {
+		got, err := RunFORM(&base, rigid, flex, &model, sorm)
+		if err != nil {
+			t.Fatalf("RunFORM(sorm %v) error = %v", sorm, err)
+		}
+		if math.Abs(got.ReliabilityIndex-wantBeta) > 1e-4 {
+			t.Errorf("RunFORM(sorm %v) beta = %v, want %v", sorm, got.ReliabilityIndex, wantBeta)
+		}
+		if math.Abs(got.FailureProbability-normalCDF(-wantBeta))/normalCDF(-wantBeta) > 1e-3 {
+			t.Errorf("RunFORM(sorm %v) failure probability = %v, want %v", sorm, got.FailureProbability, normalCDF(-wantBeta))
+		}
+		if len(got.DesignPoint) != len(sigma) {
+			t.Fatalf("RunFORM(sorm %v) design point = %+v", sorm, got.DesignPoint)
+		}
+		for key, val := range got.DesignPoint {
+			wantImportance := sigma[key] * sigma[key] / (sigmaG * sigmaG)
+			if math.Abs(val.Importance-wantImportance) > 1e-4 {
+				t.Errorf("RunFORM(sorm %v) %s importance = %v, want %v", sorm, val.Name, val.Importance, wantImportance)
+			}
+		}
+		// в расчётной точке предел текучести ниже среднего, нагрузки выше
+		if y := got.DesignPoint[0].Value; math.Abs(y-(1-wantBeta*sigma[0]/sigmaG*0.1)) > 1e-5 {
+			t.Errorf("RunFORM(sorm %v) design yield = %v", sorm, y)
+		}
+
+		if !sorm {
+			if got.Curvatures != nil || got.SORMReliabilityIndex != got.ReliabilityIndex {
+				t.Errorf("RunFORM() SORM results without sorm: %+v", got)
+			}
+			continue
+		}
+		// поверхность отказа плоская, второй порядок совпадает с первым
+		if len(got.Curvatures) != len(sigma)-1 {
+			t.Errorf("RunFORM() curvatures = %v", got.Curvatures)
+		}
+		for _, k := range got.Curvatures {
+			if math.Abs(k) > 1e-6 {
+				t.Errorf("RunFORM() curvature = %v, want 0", k)
+			}
+		}
+		if math.Abs(got.SORMReliabilityIndex-wantBeta) > 1e-4 {
+			t.Errorf("RunFORM() SORM beta = %v, want %v", got.SORMReliabilityIndex, wantBeta)
+		}
+	}
+
+	if _, err := RunFORM(&base, rigid, flex, &ReliabilityModel{}, false); err == nil {
+		t.Errorf("RunFORM() no random variables error expected")
+	}
+}
+
+func Test_solveFORM(t *testing.T) {
+	// Квадратичные функции предельного состояния с расчётной точкой на расстоянии beta от начала
+	// и известными главными кривизнами k: по Брейтунгу Pf = Φ(-beta) / Π sqrt(1 + beta·k).
+	const beta = 3
+	tests := []struct {
+		name       string
+		n          int
+		f          limitFunc
+		wantPoint  []float64
+		curvatures []float64
+	}{
+		{
+			name:       "parabola",
+			n:          2,
+			f:          func(u []float64) float64 { return beta - u[1] + 0.2/2*u[0]*u[0] },
+			wantPoint:  []float64{0, beta},
+			curvatures: []float64{0.2},
+		},
+		{
+			name: "rotated paraboloid",
+			n:    3,
+			f: func(u []float64) float64 {
+				along := (u[0] + u[1]) / math.Sqrt2
+				across := (u[0] - u[1]) / math.Sqrt2
+				return beta - along + 0.15/2*across*across - 0.1/2*u[2]*u[2]
+			},
+			wantPoint:  []float64{beta / math.Sqrt2, beta / math.Sqrt2, 0},
+			curvatures: []float64{-0.1, 0.15},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := solveFORM(tt.f, tt.n, true)
+			if err != nil {
+				t.Fatalf("solveFORM() error = %v", err)
+			}
+			if math.Abs(got.ReliabilityIndex-beta) > 1e-6 {
+				t.Errorf("solveFORM() beta = %v, want %v", got.ReliabilityIndex, beta)
+			}
+			for key, val := range got.DesignPoint {
+				if math.Abs(val.U-tt.wantPoint[key]) > 1e-6 {
+					t.Errorf("solveFORM() design point %d = %v, want %v", key, val.U, tt.wantPoint[key])
+				}
+			}
+			if len(got.Curvatures) != len(tt.curvatures) {
+				t.Fatalf("solveFORM() curvatures = %v, want %v", got.Curvatures, tt.curvatures)
+			}
+			pf := normalCDF(-beta)
+			for key, k := range tt.curvatures {
+				if math.Abs(got.Curvatures[key]-k) > 1e-6 {
+					t.Errorf("solveFORM() curvature %d = %v, want %v", key, got.Curvatures[key], k)
+				}
+				pf /= math.Sqrt(1 + beta*k)
+			}
+			if math.Abs(got.SORMFailureProbability-pf)/pf > 1e-6 {
+				t.Errorf("solveFORM() SORM failure probability = %v, want %v", got.SORMFailureProbability, pf)
+			}
+			if want := -normalInverse(pf); math.Abs(got.SORMReliabilityIndex-want) > 1e-6 {
+				t.Errorf("solveFORM() SORM beta = %v, want %v", got.SORMReliabilityIndex, want)
+			}
+		})
+	}
+}
+
+func Test_symmetricEigenvalues(t *testing.T) {
+	got := symmetricEigenvalues([][]float64{
+		{2, 1, 0},
+		{1, 2, 0},
+		{0, 0, 5},
+	})
+	want := map[float64]bool{1: true, 3: true, 5: true}
+	for _, val := range got {
+		found := false
+		for w := range want {
+			if math.Abs(val-w) < 1e-9 {
+				delete(want, w)
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("symmetricEigenvalues() unexpected %v in %v", val, got)
+		}
+	}
+}
+
+func Test_rotationMatrix(t *testing.T) {
+	alpha := []float64{0.6, 0, 0.8}
+	rot := rotationMatrix(alpha)
+	for i := range rot {
+		for j := range rot {
+			var dot float64
+			for k := range alpha {
+				dot += rot[i][k] * rot[j][k]
+			}
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if math.Abs(dot-want) > 1e-12 {
+				t.Errorf("rotationMatrix() rows %d, %d dot = %v, want %v", i, j, dot, want)
+			}
+		}
+	}
+	for k, val := range alpha {
+		if rot[len(rot)-1][k] != val {
+			t.Errorf("rotationMatrix() last row = %v, want %v", rot[len(rot)-1], alpha)
+		}
+	}
+}
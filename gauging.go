@@ -0,0 +1,160 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Gauging замеры остаточной толщины одной связи.
+type Gauging struct {
+	ID             int       // номер связи
+	ThicknessStart float64   // построечная толщина мм, для гибкой связи если 0 берётся из Flex
+	Readings       []float64 // замеры толщины мм
+	Mean           float64   // средняя замеренная толщина мм
+	Min            float64   // минимальная замеренная толщина мм
+	Thickness      float64   // толщина принятая в расчёт мм
+	Diminution     float64   // износ относительно построечной толщины %
+	Excess         bool      // признак превышения допускаемого износа
+}
+
+// GaugingData данные дефектации корпуса.
+type GaugingData struct {
+	Allowable float64         // допускаемый износ %
+	UseMin    bool            // false расчёт по средней толщине, true по минимальной
+	Rigid     map[int]Gauging // замеры жёстких связей
+	Flex      map[int]Gauging // замеры гибких связей
+}
+
+// GaugingIDs возвращает номера связей с замерами по возрастанию для обхода карты в постоянном порядке.
+func GaugingIDs(data map[int]Gauging) []int {
+	rez := make([]int, 0, len(data))
+	for id := range data {
+		rez = append(rez, id)
+	}
+	sort.Ints(rez)
+	return rez
+}
+
+// calcStatistics считает среднюю и минимальную толщину по замерам.
+func (g *Gauging) calcStatistics() error {
+	if len(g.Readings) == 0 {
+		return fmt.Errorf("no readings for element %d", g.ID)
+	}
+	var sum float64
+	g.Min = g.Readings[0]
+	for _, val := range g.Readings {
+		if val <= 0 {
+			return fmt.Errorf("bad reading %v for element %d", val, g.ID)
+		}
+		sum += val
+		g.Min = math.Min(g.Min, val)
+	}
+	g.Mean = sum / float64(len(g.Readings))
+	return nil
+}
+
+// calc считает толщину принятую в расчёт, износ и проверяет его на допускаемый.
+func (g *Gauging) calc(allowable float64, useMin bool) error {
+	if g.ThicknessStart <= 0 {
+		return fmt.Errorf("no start thickness for element %d", g.ID)
+	}
+	err := g.calcStatistics()
+	if err != nil {
+		return err
+	}
+	g.Thickness = g.Mean
+	if useMin {
+		g.Thickness = g.Min
+	}
+	g.Diminution = calcDiminution(g.ThicknessStart, g.Thickness)
+	g.Excess = allowable > 0 && g.Diminution > allowable
+	return nil
+}
+
+// calcDiminution считает износ в % от построечной толщины.
+func calcDiminution(start, end float64) float64 {
+	rez := (start - end) / start * 100
+	return rez
+}
+
+// applyGauging заменяет толщину гибкой связи замеренной.
+func (f *Flex) applyGauging(g *Gauging) {
+	f.ThicknessEnd = g.Thickness
+	f.AreaEnd = (g.Thickness / 10) * f.Width * f.Count
+	f.StaticMoment = calcStaticMoment(f.AreaEnd, f.Height)
+	f.MomentOfInertia = calcMomentOfInertia(f.AreaEnd, f.Height)
+}
+
+// applyGauging заменяет площадь жёсткой связи пропорционально замеренной толщине.
+func (r *Rigid) applyGauging(g *Gauging) {
+	r.AreaEnd = r.AreaStart * g.Thickness / g.ThicknessStart * r.Count
+	r.StaticMoment = calcStaticMoment(r.AreaEnd, r.Height)
+	r.MomentOfInertia = calcMomentOfInertia(r.AreaEnd, r.Height)
+}
+
+// ApplyGauging считает износ по замерам и заменяет им расчётные толщины и площади связей.
+// Связи без замеров остаются с износом посчитанным по скорости коррозии.
+// При ошибке в любом замере данные и связи не изменяются.
+func ApplyGauging(data *GaugingData, rigid map[int]Rigid, flex map[int]Flex) error {
+	gaugedRigid := make(map[int]Gauging, len(data.Rigid))
+	newRigid := make(map[int]Rigid, len(data.Rigid))
+	for _, key := range GaugingIDs(data.Rigid) {
+		g := data.Rigid[key]
+		r, ok := rigid[g.ID]
+		if !ok {
+			return fmt.Errorf("gauging of missing rigid %d", g.ID)
+		}
+		err := g.calc(data.Allowable, data.UseMin)
+		if err != nil {
+			return err
+		}
+		r.applyGauging(&g)
+		newRigid[g.ID] = r
+		gaugedRigid[key] = g
+	}
+
+	gaugedFlex := make(map[int]Gauging, len(data.Flex))
+	newFlex := make(map[int]Flex, len(data.Flex))
+	for _, key := range GaugingIDs(data.Flex) {
+		g := data.Flex[key]
+		f, ok := flex[g.ID]
+		if !ok {
+			return fmt.Errorf("gauging of missing flex %d", g.ID)
+		}
+		if g.ThicknessStart == 0 {
+			g.ThicknessStart = f.ThicknessStart
+		}
+		err := g.calc(data.Allowable, data.UseMin)
+		if err != nil {
+			return err
+		}
+		f.applyGauging(&g)
+		newFlex[g.ID] = f
+		gaugedFlex[key] = g
+	}
+
+	for id, r := range newRigid {
+		rigid[id] = r
+	}
+	for key, g := range gaugedRigid {
+		data.Rigid[key] = g
+	}
+	for id, f := range newFlex {
+		flex[id] = f
+	}
+	for key, g := range gaugedFlex {
+		data.Flex[key] = g
+	}
+	return nil
+}
+
+// CalculateGauged считает сечение по фактическим замеренным толщинам.
+// Связи должны быть предварительно посчитаны CalcAllRigid и CalcAllFlex.
+func CalculateGauged(baseData *BaseData, data *GaugingData, rigid map[int]Rigid, flex map[int]Flex) (Iterations, error) {
+	err := ApplyGauging(data, rigid, flex)
+	if err != nil {
+		return nil, err
+	}
+	return Calculate(baseData, rigid, flex), nil
+}
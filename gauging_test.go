@@ -0,0 +1,124 @@
+package strength
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGauging_calc(t *testing.T) {
+	type args struct {
+		allowable float64
+		useMin    bool
+	}
+	tests := []struct {
+		name           string
+		g              Gauging
+		args           args
+		wantThickness  float64
+		wantDiminution float64
+		wantExcess     bool
+		wantErr        bool
+	}{
+		{
+			name:           "mean thickness",
+			g:              Gauging{ID: 1, ThicknessStart: 10, Readings: []float64{9, 8, 7}},
+			args:           args{allowable: 25},
+			wantThickness:  8,
+			wantDiminution: 20,
+		},
+		{
+			name:           "min thickness excess",
+			g:              Gauging{ID: 1, ThicknessStart: 10, Readings: []float64{9, 8, 7}},
+			args:           args{allowable: 25, useMin: true},
+			wantThickness:  7,
+			wantDiminution: 30,
+			wantExcess:     true,
+		},
+		{
+			name:    "no readings",
+			g:       Gauging{ID: 1, ThicknessStart: 10},
+			wantErr: true,
+		},
+		{
+			name:    "no start thickness",
+			g:       Gauging{ID: 1, Readings: []float64{9}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.g.calc(tt.args.allowable, tt.args.useMin)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Gauging.calc() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.g.Thickness != tt.wantThickness {
+				t.Errorf("Gauging.calc() thickness = %v, want %v", tt.g.Thickness, tt.wantThickness)
+			}
+			if tt.g.Diminution != tt.wantDiminution {
+				t.Errorf("Gauging.calc() diminution = %v, want %v", tt.g.Diminution, tt.wantDiminution)
+			}
+			if tt.g.Excess != tt.wantExcess {
+				t.Errorf("Gauging.calc() excess = %v, want %v", tt.g.Excess, tt.wantExcess)
+			}
+		})
+	}
+}
+
+func TestApplyGauging(t *testing.T) {
+	rigid := map[int]Rigid{1: {ID: 1, AreaStart: 20, Height: 2, Count: 2}}
+	flex := map[int]Flex{1: {ID: 1, Width: 60, ThicknessStart: 10, Height: 1, Count: 1}}
+	data := GaugingData{
+		Rigid: map[int]Gauging{1: {ID: 1, ThicknessStart: 10, Readings: []float64{8}}},
+		Flex:  map[int]Gauging{1: {ID: 1, Readings: []float64{9, 7}}},
+	}
+	err := ApplyGauging(&data, rigid, flex)
+	if err != nil {
+		t.Fatalf("ApplyGauging() error = %v", err)
+	}
+	if got := rigid[1].AreaEnd; got != 32 {
+		t.Errorf("ApplyGauging() rigid area = %v, want %v", got, 32)
+	}
+	if got := rigid[1].StaticMoment; got != 64 {
+		t.Errorf("ApplyGauging() rigid static moment = %v, want %v", got, 64)
+	}
+	if got := flex[1].ThicknessEnd; got != 8 {
+		t.Errorf("ApplyGauging() flex thickness = %v, want %v", got, 8)
+	}
+	if got := flex[1].AreaEnd; got != 48 {
+		t.Errorf("ApplyGauging() flex area = %v, want %v", got, 48)
+	}
+
+	data.Flex[2] = Gauging{ID: 2, Readings: []float64{9}}
+	if err := ApplyGauging(&data, rigid, flex); err == nil {
+		t.Errorf("ApplyGauging() missing element error expected")
+	}
+}
+
+func TestApplyGauging_atomic(t *testing.T) {
+	rigid := map[int]Rigid{1: {ID: 1, AreaStart: 20, AreaEnd: 36, Height: 2, Count: 2}}
+	flex := map[int]Flex{1: {ID: 1, Width: 60, ThicknessStart: 10, ThicknessEnd: 9, AreaEnd: 54, Height: 1, Count: 1}}
+	data := GaugingData{
+		Rigid: map[int]Gauging{1: {ID: 1, ThicknessStart: 10, Readings: []float64{8}}},
+		Flex:  map[int]Gauging{1: {ID: 1, Readings: []float64{-1}}},
+	}
+	wantRigid := map[int]Rigid{1: rigid[1]}
+	wantFlex := map[int]Flex{1: flex[1]}
+	wantData := map[int]Gauging{1: data.Rigid[1]}
+
+	if err := ApplyGauging(&data, rigid, flex); err == nil {
+		t.Fatalf("ApplyGauging() bad reading error expected")
+	}
+	if !reflect.DeepEqual(rigid, wantRigid) {
+		t.Errorf("ApplyGauging() rigid changed on error: %+v", rigid[1])
+	}
+	if !reflect.DeepEqual(flex, wantFlex) {
+		t.Errorf("ApplyGauging() flex changed on error: %+v", flex[1])
+	}
+	if !reflect.DeepEqual(data.Rigid, wantData) {
+		t.Errorf("ApplyGauging() gauging changed on error: %+v", data.Rigid[1])
+	}
+}
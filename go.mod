@@ -1,9 +1,10 @@
 module github.com/kenits/strength
 
-go 1.13
+go 1.16
 
 require (
 	github.com/360EntSecGroup-Skylar/excelize/v2 v2.0.0
 	github.com/cweill/gotests v1.5.3 // indirect
 	golang.org/x/tools v0.0.0-20190610165438-e9a20a139658 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
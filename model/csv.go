@@ -0,0 +1,310 @@
+package model
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+
+	str "github.com/kenits/strength"
+)
+
+// readCSV читает записи CSV, строки начинающиеся с # считаются комментариями.
+// Разделитель, запятая или точка с запятой, выбирается по разобранному заголовку:
+// тот, при котором больше ячеек заголовка подходят столбцам columns, при равенстве больше ячеек, иначе запятая.
+func readCSV(r io.Reader, columns []Column) ([][]string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		rez                  [][]string
+		firstErr             error
+		bestScore, bestCells = -1, -1
+	)
+	for _, comma := range []rune{',', ';'} {
+		records, err := parseCSV(data, comma)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		var score, cells int
+		if len(records) != 0 {
+			cells = len(records[0])
+			for _, cell := range records[0] {
+				for key := range columns {
+					if columns[key].Match(cell) {
+						score++
+						break
+					}
+				}
+			}
+		}
+		if score > bestScore || score == bestScore && cells > bestCells {
+			rez = records
+			bestScore, bestCells = score, cells
+		}
+	}
+	if bestScore < 0 {
+		return nil, firstErr
+	}
+	return rez, nil
+}
+
+// parseCSV разбирает записи CSV с разделителем comma.
+func parseCSV(data []byte, comma rune) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = comma
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return reader.ReadAll()
+}
+
+// ReadRigidCSV читает таблицу жёстких связей из CSV, столбцы по заголовку как в RigidTable.
+func ReadRigidCSV(r io.Reader) ([]Rigid, error) {
+	records, err := readCSV(r, RigidColumns)
+	if err != nil {
+		return nil, err
+	}
+	return RigidTable(records)
+}
+
+// ReadFlexCSV читает таблицу гибких связей из CSV, столбцы по заголовку как в FlexTable.
+func ReadFlexCSV(r io.Reader) ([]Flex, error) {
+	records, err := readCSV(r, FlexColumns)
+	if err != nil {
+		return nil, err
+	}
+	return FlexTable(records)
+}
+
+// header заголовок столбца с единицей в квадратных скобках.
+func header(name, unit string) string {
+	if unit == "" {
+		return name
+	}
+	return name + " [" + unit + "]"
+}
+
+// columnsHeader заголовок таблицы связей.
+func columnsHeader(columns []Column) []string {
+	rez := make([]string, len(columns))
+	for key, val := range columns {
+		rez[key] = header(val.Name, val.Unit)
+	}
+	return rez
+}
+
+// formatFloat число для CSV без потери точности. Бесконечность и NaN пишутся пустой ячейкой,
+// например момент сопротивления в точке на нейтральной оси.
+func formatFloat(val float64) string {
+	if math.IsInf(val, 0) || math.IsNaN(val) {
+		return ""
+	}
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
+
+// checkFinite ошибка, если у связи kind с номером id есть бесконечное значение или NaN:
+// пустая ячейка вместо него прочиталась бы обратно как значение по умолчанию.
+func checkFinite(kind string, id int, vals ...float64) error {
+	for _, val := range vals {
+		if math.IsInf(val, 0) || math.IsNaN(val) {
+			return fmt.Errorf("%s %d: non-finite value %v", kind, id, val)
+		}
+	}
+	return nil
+}
+
+// writeCSV пишет записи CSV через запятую.
+func writeCSV(w io.Writer, records [][]string) error {
+	writer := csv.NewWriter(w)
+	err := writer.WriteAll(records)
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// WriteRigidCSV пишет таблицу жёстких связей в CSV с единицами в заголовке.
+func WriteRigidCSV(w io.Writer, data []Rigid) error {
+	records := [][]string{columnsHeader(RigidColumns)}
+	for _, val := range data {
+		err := checkFinite("rigid", val.ID, val.Area, val.Corrosion, val.Height, val.HalfBreadth, val.Count)
+		if err != nil {
+			return err
+		}
+		records = append(records, []string{
+			strconv.Itoa(val.ID),
+			val.Name,
+			formatFloat(val.Area),
+			formatFloat(val.Corrosion),
+			formatFloat(val.Height),
+			formatFloat(val.HalfBreadth),
+			val.Side,
+			formatFloat(val.Count),
+		})
+	}
+	return writeCSV(w, records)
+}
+
+// WriteFlexCSV пишет таблицу гибких связей в CSV с единицами в заголовке.
+func WriteFlexCSV(w io.Writer, data []Flex) error {
+	records := [][]string{columnsHeader(FlexColumns)}
+	for _, val := range data {
+		err := checkFinite("flex", val.ID, val.Length, val.Width, val.Thickness, val.Corrosion, val.Height, val.HalfBreadth, val.Count, val.Pressure)
+		if err != nil {
+			return err
+		}
+		records = append(records, []string{
+			strconv.Itoa(val.ID),
+			val.Name,
+			formatFloat(val.Length),
+			formatFloat(val.Width),
+			formatFloat(val.Thickness),
+			formatFloat(val.Corrosion),
+			formatFloat(val.Height),
+			formatFloat(val.HalfBreadth),
+			val.Side,
+			formatFloat(val.Count),
+			formatFloat(val.Pressure),
+		})
+	}
+	return writeCSV(w, records)
+}
+
+// WriteSectionsCSV пишет характеристики сечения по приближениям, по строке на приближение.
+func WriteSectionsCSV(w io.Writer, data str.Iterations) error {
+	records := [][]string{{
+		"iteration",
+		header("area", "cm2"),
+		header("static_moment", "cm2*m"),
+		header("center_of_mass", "m"),
+		header("moment_of_inertia", "cm2*m2"),
+		header("moment", "kN*m"),
+		header("area_loss", "cm2"),
+		header("static_moment_loss", "cm2*m"),
+		header("moment_of_inertia_loss", "cm2*m2"),
+		header("half_breadth", "m"),
+		header("transverse_inertia", "cm2*m2"),
+		header("product_of_inertia", "cm2*m2"),
+		header("neutral_axis_angle", "rad"),
+		header("principal_angle", "rad"),
+		header("principal_inertia", "cm2*m2"),
+		header("principal_transverse_inertia", "cm2*m2"),
+	}}
+	for _, it := range data {
+		r := &it.Rezult
+		records = append(records, []string{
+			strconv.Itoa(it.ID),
+			formatFloat(r.Area),
+			formatFloat(r.StaticMoment),
+			formatFloat(r.CenterOfMass),
+			formatFloat(r.MomentOfInertia),
+			formatFloat(r.Moment),
+			formatFloat(r.AreaLoss),
+			formatFloat(r.StaticMomentLoss),
+			formatFloat(r.MomentOfInertiaLoss),
+			formatFloat(r.HalfBreadth),
+			formatFloat(r.TransverseInertia),
+			formatFloat(r.ProductOfInertia),
+			formatFloat(r.NeutralAxisAngle),
+			formatFloat(r.PrincipalAngle),
+			formatFloat(r.PrincipalInertia),
+			formatFloat(r.PrincipalTransverseInertia),
+		})
+	}
+	return writeCSV(w, records)
+}
+
+// WritePointsCSV пишет величины в контрольных точках по приближениям,
+// по строке на точку приближения. Предельный момент пуст при заданном расчётном моменте, напряжение наоборот.
+func WritePointsCSV(w io.Writer, data str.Iterations) error {
+	records := [][]string{{
+		"iteration",
+		header("height", "m"),
+		header("moment_of_resistance", "cm2*m"),
+		header("moment", "kN*m"),
+		header("strain", "kN/cm2"),
+	}}
+	for _, it := range data {
+		r := &it.Rezult
+		for key, height := range r.Heigth {
+			record := []string{strconv.Itoa(it.ID), formatFloat(height), formatFloat(r.MomentsOfResistance[key]), "", ""}
+			if key < len(r.Moments) {
+				record[3] = formatFloat(r.Moments[key])
+			}
+			if key < len(r.Strain) {
+				record[4] = formatFloat(r.Strain[key])
+			}
+			records = append(records, record)
+		}
+	}
+	return writeCSV(w, records)
+}
+
+// WritePlatesCSV пишет редукцию пластин по приближениям, по строке на пластину приближения.
+func WritePlatesCSV(w io.Writer, data str.Iterations) error {
+	records := [][]string{{
+		"iteration",
+		"id",
+		"key",
+		"reducing",
+		"reverse_reducing",
+		header("reducing_area", "cm2"),
+		header("area_loss", "cm2"),
+		header("height", "m"),
+		header("half_breadth", "m"),
+		header("static_moment_loss", "cm2*m"),
+		header("moment_of_inertia_loss", "cm2*m2"),
+		header("length", "cm"),
+		header("width", "cm"),
+		header("thickness", "mm"),
+		header("pressure", "kPa"),
+		"count",
+		header("actual_strain", "kN/cm2"),
+		header("eulerian_strain", "kN/cm2"),
+		header("chain_strain", "kN/cm2"),
+		"rho",
+		"kappa",
+		header("start_curvature", "cm"),
+		header("press_curvature", "cm"),
+		"x",
+	}}
+	for _, it := range data {
+		for _, a := range it.Approx {
+			records = append(records, []string{
+				strconv.Itoa(it.ID),
+				strconv.Itoa(a.ID),
+				strconv.Itoa(a.Key),
+				formatFloat(a.Reducing),
+				formatFloat(a.ReverseReducing),
+				formatFloat(a.ReducingArea),
+				formatFloat(a.AreaLoss),
+				formatFloat(a.Height),
+				formatFloat(a.HalfBreadth),
+				formatFloat(a.StaticMomentLoss),
+				formatFloat(a.MomentOfInertiaLoss),
+				formatFloat(a.Length),
+				formatFloat(a.Width),
+				formatFloat(a.Thickness),
+				formatFloat(a.Pressure),
+				formatFloat(a.Count),
+				formatFloat(a.ActualStrain),
+				formatFloat(a.EulerianStrain),
+				formatFloat(a.ChainStrain),
+				formatFloat(a.Rho),
+				formatFloat(a.Kappa),
+				formatFloat(a.StartCurvature),
+				formatFloat(a.PressCurvature),
+				formatFloat(a.X),
+			})
+		}
+	}
+	return writeCSV(w, records)
+}
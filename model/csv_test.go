@@ -0,0 +1,328 @@
+package model
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	str "github.com/kenits/strength"
+)
+
+func Test_unitScale(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     float64
+		wantErr  bool
+	}{
+		{"mm", "cm", 0.1, false},
+		{"М", "cm", 100, false},
+		{"мм²", "cm2", 0.01, false},
+		{"MPa", "kPa", 1000, false},
+		{"мм/год", "mm/year", 1, false},
+		{"cm / year", "mm/year", 10, false},
+		{"mm", "cm2", 0, true},
+		{"mm/year", "mm", 0, true},
+		{"inch", "cm", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := unitScale(tt.from, tt.to)
+		if (err != nil) != tt.wantErr || math.Abs(got-tt.want) > 1e-12*tt.want {
+			t.Errorf("unitScale(%q, %q) = %v, %v, want %v", tt.from, tt.to, got, err, tt.want)
+		}
+	}
+}
+
+func Test_splitHeader(t *testing.T) {
+	tests := []struct {
+		header, name, unit string
+	}{
+		{"area [cm2]", "area", "cm2"},
+		{"Площадь (мм2)", "Площадь", "мм2"},
+		{"Толщина, мм", "Толщина", "мм"},
+		{" id ", "id", ""},
+	}
+	for _, tt := range tests {
+		name, unit := splitHeader(tt.header)
+		if name != tt.name || unit != tt.unit {
+			t.Errorf("splitHeader(%q) = %q, %q, want %q, %q", tt.header, name, unit, tt.name, tt.unit)
+		}
+	}
+}
+
+func TestReadFlexCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Flex
+		wantErr string
+	}{
+		{
+			name: "english",
+			data: "# плиты\nid,height [m],thickness [cm],width [mm],length [m],pressure [MPa],name\n1,0.5,1.2,600,2.4,0.05,Днище\n\n",
+			want: []Flex{{ID: 1, Name: "Днище", Length: 240, Width: 60, Thickness: 12, Height: 0.5, Count: 1, Pressure: 50}},
+		},
+		{
+			name: "russian",
+			data: "№;Наименование;Длина, см;Ширина, см;Толщина, мм;Коррозия, мм/год;Высота, м;Полуширота, м;Борт;Кол-во\n" +
+				"2;Борт;240;60;10,5;0,1;5;7;ПБ;3\n",
+			want: []Flex{{ID: 2, Name: "Борт", Length: 240, Width: 60, Thickness: 10.5, Corrosion: 0.1, Height: 5, HalfBreadth: 7, Side: "starboard", Count: 3}},
+		},
+		{
+			name: "comma with semicolon in header",
+			data: "id,length,width,thickness,height,note; comment\n1,240,60,8,0,x\n",
+			want: []Flex{{ID: 1, Length: 240, Width: 60, Thickness: 8, Count: 1}},
+		},
+		{
+			name: "semicolon after empty line",
+			data: "\n# плиты\nid;length;width;thickness;height;name\n1;240;60;8,5;0;днище, кормовая часть\n",
+			want: []Flex{{ID: 1, Name: "днище, кормовая часть", Length: 240, Width: 60, Thickness: 8.5, Count: 1}},
+		},
+		{
+			name:    "missing column",
+			data:    "id,length,width,height\n1,240,60,0\n",
+			wantErr: `missing column "thickness"`,
+		},
+		{
+			name:    "bad unit",
+			data:    "id,length [kPa],width,thickness,height\n1,240,60,8,0\n",
+			wantErr: `B1: length: "length [kPa]" is not unit compatible with cm`,
+		},
+		{
+			name:    "bad number",
+			data:    "id,length,width,thickness,height\n1,240,60,x,0\n",
+			wantErr: `D2: thickness: "x" is not number`,
+		},
+		{
+			name:    "bad side",
+			data:    "id,length,width,thickness,height,side\n1,240,60,8,0,left\n",
+			wantErr: `F2: side: "left" is not side`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadFlexCSV(strings.NewReader(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ReadFlexCSV() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadFlexCSV() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ReadFlexCSV() = %+v, want %+v", got, tt.want)
+			}
+			for key := range got {
+				g, w := got[key], tt.want[key]
+				for _, pair := range [][2]float64{{g.Length, w.Length}, {g.Width, w.Width}, {g.Thickness, w.Thickness}, {g.Pressure, w.Pressure}} {
+					if math.Abs(pair[0]-pair[1]) > 1e-9 {
+						t.Errorf("ReadFlexCSV() = %+v, want %+v", g, w)
+					}
+				}
+				g.Length, g.Width, g.Thickness, g.Pressure = w.Length, w.Width, w.Thickness, w.Pressure
+				if !reflect.DeepEqual(g, w) {
+					t.Errorf("ReadFlexCSV() = %+v, want %+v", g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCSV_roundTrip(t *testing.T) {
+	base, rigid, flex, _ := testModel()
+	doc := New(base, rigid, flex, nil)
+
+	var buf bytes.Buffer
+	err := WriteRigidCSV(&buf, doc.Rigid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotRigid, err := ReadRigidCSV(&buf)
+	if err != nil || !reflect.DeepEqual(gotRigid, doc.Rigid) {
+		t.Errorf("ReadRigidCSV() = %+v, %v, want %+v", gotRigid, err, doc.Rigid)
+	}
+
+	buf.Reset()
+	err = WriteFlexCSV(&buf, doc.Flex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flexCSV := buf.String()
+	gotFlex, err := ReadFlexCSV(&buf)
+	if err != nil || !reflect.DeepEqual(gotFlex, doc.Flex) {
+		t.Errorf("ReadFlexCSV() = %+v, %v, want %+v", gotFlex, err, doc.Flex)
+	}
+
+	// та же таблица через точку с запятой
+	records, err := csv.NewReader(strings.NewReader(flexCSV)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	writer := csv.NewWriter(&buf)
+	writer.Comma = ';'
+	if err = writer.WriteAll(records); err != nil {
+		t.Fatal(err)
+	}
+	gotFlex, err = ReadFlexCSV(&buf)
+	if err != nil || !reflect.DeepEqual(gotFlex, doc.Flex) {
+		t.Errorf("ReadFlexCSV(;) = %+v, %v, want %+v", gotFlex, err, doc.Flex)
+	}
+
+	rigidInf := []Rigid{{ID: 1, Area: math.Inf(1), Count: 1}}
+	if err := WriteRigidCSV(&buf, rigidInf); err == nil {
+		t.Errorf("WriteRigidCSV() non-finite error expected")
+	}
+	flexNaN := []Flex{{ID: 1, Length: 240, Width: 60, Thickness: math.NaN(), Count: 1}}
+	if err := WriteFlexCSV(&buf, flexNaN); err == nil {
+		t.Errorf("WriteFlexCSV() non-finite error expected")
+	}
+}
+
+func TestWritePointsCSV_nonFinite(t *testing.T) {
+	// контрольная точка на нейтральной оси: момент сопротивления бесконечен
+	data := str.Iterations{{ID: 1, Rezult: str.Rezult{
+		Heigth:              []float64{0, 5},
+		MomentsOfResistance: []float64{1000, math.Inf(1)},
+		Strain:              []float64{10, math.NaN()},
+	}}}
+	var buf bytes.Buffer
+	if err := WritePointsCSV(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"1", "0", "1000", "", "10"}, {"1", "5", "", "", ""}}
+	if !reflect.DeepEqual(records[1:], want) {
+		t.Errorf("WritePointsCSV() = %v, want %v", records[1:], want)
+	}
+}
+
+func TestWriteRezultCSV(t *testing.T) {
+	base, rigid, flex, _ := testModel()
+	str.CalcAllRigid(rigid, base.Age)
+	str.CalcAllFlex(flex, base.Age)
+	data := str.Calculate(base, rigid, flex)
+
+	plates := 0
+	for _, it := range data {
+		plates += len(it.Approx)
+	}
+	tests := []struct {
+		name  string
+		write func(buf *bytes.Buffer) error
+		rows  int
+	}{
+		{"sections", func(buf *bytes.Buffer) error { return WriteSectionsCSV(buf, data) }, len(data)},
+		{"points", func(buf *bytes.Buffer) error { return WritePointsCSV(buf, data) }, len(data) * len(base.Height)},
+		{"plates", func(buf *bytes.Buffer) error { return WritePlatesCSV(buf, data) }, plates},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := tt.write(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != tt.rows+1 {
+				t.Errorf("rows = %d, want %d", len(records)-1, tt.rows)
+			}
+			if last := records[len(records)-1]; last[0] != strconv.Itoa(len(data)) || len(last) != len(records[0]) {
+				t.Errorf("last row = %v", last)
+			}
+		})
+	}
+}
+
+func TestPointTable(t *testing.T) {
+	records := [][]string{
+		{"Высота, м", "Допускаемое напряжение, МПа"},
+		{"0", "235"},
+		{"", ""},
+		{"10", "315"},
+	}
+	got, err := PointTable(records)
+	want := []Point{{0, 23.5}, {10, 31.5}}
+	if err != nil || len(got) != len(want) {
+		t.Fatalf("PointTable() = %v, %v, want %v", got, err, want)
+	}
+	for key := range got {
+		if got[key].Height != want[key].Height || math.Abs(got[key].Strain-want[key].Strain) > 1e-12 {
+			t.Errorf("PointTable() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestColumn_Match(t *testing.T) {
+	c := Column{Name: "half_breadth", Aliases: []string{"полуширота"}}
+	for _, val := range []string{"half breadth", "HalfBreadth", "Полуширота, м", "half_breadth [m]"} {
+		if !c.Match(val) {
+			t.Errorf("Match(%q) = false", val)
+		}
+	}
+	if c.Match("Ширина") {
+		t.Errorf("Match(%q) = true", "Ширина")
+	}
+}
+
+func TestFlexTable_errors(t *testing.T) {
+	records := [][]string{
+		{"id", "length", "width", "thickness", "height", "pressure [MPa]"},
+		{"1", "240", "60", "8", "0", ""},
+		{"x", "240", "6O", "8", "0", ""},
+		{},
+		{"3", "240", "60", "8", "", "0,1"},
+		{"4", "240", "60", "8,5.", "0", "a"},
+		{"3", "240", "60", "8", "0", ""},
+	}
+	_, err := FlexTable(records)
+	errs, ok := err.(InputErrors)
+	if !ok {
+		t.Fatalf("FlexTable() error = %v, want InputErrors", err)
+	}
+	want := []string{"A3", "C3", "E5", "D6", "F6", "A7"}
+	if len(errs) != len(want) {
+		t.Fatalf("FlexTable() error = %v, want %d errors", err, len(want))
+	}
+	for key, val := range want {
+		if got := errs[key].Cell(); got != val {
+			t.Errorf("FlexTable() error %d cell = %v, want %v", key, got, val)
+		}
+	}
+	if errs[0].Expected != "integer" || errs[1].Value != "6O" || errs[4].Field != "pressure" || errs[5].Expected != "unique id" {
+		t.Errorf("FlexTable() errors = %+v", errs)
+	}
+
+	errs.Locate("Гибкие связи", 3, 2)
+	if got := errs[0].Error(); got != `Гибкие связи!B5: id: "x" is not integer` {
+		t.Errorf("Locate() error = %v", got)
+	}
+}
+
+func TestInputError_Error(t *testing.T) {
+	tests := []struct {
+		err  InputError
+		want string
+	}{
+		{InputError{Sheet: "Лист", Row: 3, Column: 28, Field: "area", Value: "x", Expected: "number"}, `Лист!AB3: area: "x" is not number`},
+		{InputError{Row: 2, Column: 1, Value: "", Expected: "number"}, `A2: "" is not number`},
+		{InputError{Sheet: "Лист", Row: 1, Expected: `column "area"`}, `Лист!1:1: missing column "area"`},
+		{InputError{Sheet: "Лист", Expected: "sheet"}, `Лист: missing sheet`},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %v, want %v", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Package model чтение и запись модели сечения и результатов расчёта в JSON и YAML,
+// чтобы модели можно было хранить в git и создавать скриптами.
+//
+// Документ модели версии 1 (YAML, в JSON те же имена полей):
+//
+//	version: 1                  # версия схемы, обязательна
+//	project: "Проект"           # проект
+//	name: "Мидель"              # имя расчёта
+//	age: 20                     # срок службы лет
+//	symmetry: true              # связи без указания борта задают половину сечения
+//	hogging: false              # false прогиб, true перегиб
+//	moment: 0                   # расчётный момент кН*м, если 0 то считается предельный
+//	accuracy: 0.01              # точность расчёта %
+//	points:                     # расчётные точки по высоте
+//	  - {height: 0, strain: 23.5}          # высота м, допускаемое напряжение кН/см2
+//	material:
+//	  name: "Сталь"
+//	  elastic_modul: 2.06e8     # модуль упругости кПа
+//	rigid:                      # жёсткие связи
+//	  - id: 1
+//	    name: "Киль"
+//	    area: 100               # площадь в начале срока службы см2
+//	    corrosion: 0.1          # годовая коррозия см2/год
+//	    height: 0               # центр тяжести от ОП м
+//	    half_breadth: 0         # полуширота от ДП м, правый борт положительный
+//	    side: ""                # "", both, centre, port, starboard
+//	    count: 1                # количество
+//	flex:                       # гибкие связи (пластины)
+//	  - id: 1
+//	    name: "Днище"
+//	    length: 240             # длина вдоль судна см
+//	    width: 60               # ширина поперёк судна см
+//	    thickness: 12           # толщина мм
+//	    corrosion: 0.1          # годовая коррозия мм/год
+//	    height: 0
+//	    half_breadth: 1.5
+//	    side: ""
+//	    count: 10
+//	    pressure: 0             # поперечная нагрузка кПа
+//	load_cases:                 # случаи загрузки, могут отсутствовать
+//	  - name: "Полный груз"
+//	    draft: 8                # осадка м
+//	    sea_density: 1.025      # плотность забортной воды т/м3, если 0 то 1.025
+//	    wave: {length: 150, speed: 14, distribution: 1}   # нет на тихой воде
+//	    tanks:
+//	      - {name: "Танк 1", filling: 10, density: 0.85, overpressure: 20}
+//	    exposure:               # нагрузки на пластины по номерам гибких связей
+//	      - {flex: 1, sea: true, tank: "Танк 1"}
+//
+// Документ результатов содержит те же сведения о проекте и по одному результату
+// на каждый случай загрузки (один без имени случая, если их нет):
+// сходимость, итоговые характеристики сечения и все приближения с редукцией пластин.
+//
+// Таблицы жёстких и гибких связей читаются также из CSV: столбцы находятся по заголовку
+// (имена схемы или русские названия, см. RigidColumns и FlexColumns) в любом порядке,
+// единица в заголовке ("Толщина, мм", "area [mm2]") переводится в единицу модели.
+// Разделитель запятая или точка с запятой, с точкой с запятой допускается десятичная запятая.
+// Ошибки значений всех строк возвращаются разом как InputErrors со строкой и столбцом таблицы
+// (строки комментариев не считаются), Locate привязывает их к листу книги.
+// Результаты по приближениям пишутся в CSV тремя таблицами: характеристики сечения,
+// величины в контрольных точках и редукция пластин, по строке на приближение, точку или пластину.
+package model
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// InputError ошибка во входных данных: значение ячейки не того типа или нет нужного столбца, подписи, листа.
+type InputError struct {
+	Sheet    string // лист книги или файл, пусто если неизвестно
+	Row      int    // строка с 1, 0 если ячейки нет
+	Column   int    // столбец с 1, 0 если ошибка относится ко всей строке или ячейки нет
+	Field    string // имя поля
+	Value    string // исходное значение
+	Expected string // ожидаемый тип значения или описание недостающего
+}
+
+// Cell адрес ячейки вида B3, строки вида 3:3 или пусто.
+func (e *InputError) Cell() string {
+	switch {
+	case e.Row == 0:
+		return ""
+	case e.Column == 0:
+		return fmt.Sprintf("%d:%d", e.Row, e.Row)
+	}
+	return ColumnName(e.Column) + strconv.Itoa(e.Row)
+}
+
+// Error описание ошибки с местом.
+func (e *InputError) Error() string {
+	loc := e.Cell()
+	if e.Sheet != "" && loc != "" {
+		loc = e.Sheet + "!" + loc
+	} else if e.Sheet != "" {
+		loc = e.Sheet
+	}
+	if e.Column == 0 {
+		return fmt.Sprintf("%s: missing %s", loc, e.Expected)
+	}
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %q is not %s", loc, e.Value, e.Expected)
+	}
+	return fmt.Sprintf("%s: %s: %q is not %s", loc, e.Field, e.Value, e.Expected)
+}
+
+// InputErrors все ошибки входных данных.
+type InputErrors []InputError
+
+// Error перечисление ошибок.
+func (e InputErrors) Error() string {
+	rez := make([]string, len(e))
+	for key := range e {
+		rez[key] = e[key].Error()
+	}
+	return fmt.Sprintf("%d input errors: %s", len(e), strings.Join(rez, "; "))
+}
+
+// Err nil если ошибок нет.
+func (e InputErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Locate привязывает ошибки таблицы к листу sheet: строки и столбцы таблицы считаются от ячейки row, col.
+func (e InputErrors) Locate(sheet string, row, col int) {
+	for key := range e {
+		e[key].Sheet = sheet
+		if e[key].Row != 0 {
+			e[key].Row += row - 1
+		}
+		if e[key].Column != 0 {
+			e[key].Column += col - 1
+		}
+	}
+}
+
+// Collect добавляет к errs ошибки входных данных из err и возвращает nil, прочие ошибки возвращает.
+func Collect(errs *InputErrors, err error) error {
+	switch e := err.(type) {
+	case InputErrors:
+		*errs = append(*errs, e...)
+		return nil
+	case *InputError:
+		*errs = append(*errs, *e)
+		return nil
+	}
+	return err
+}
+
+// ColumnName имя столбца по номеру с 1: A, B, ..., Z, AA.
+func ColumnName(col int) string {
+	var rez []byte
+	for ; col > 0; col = (col - 1) / 26 {
+		rez = append([]byte{byte('A' + (col-1)%26)}, rez...)
+	}
+	return string(rez)
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format формат документа.
+type Format int
+
+const (
+	// JSON формат JSON.
+	JSON Format = iota
+	// YAML формат YAML.
+	YAML
+)
+
+// FormatOf определяет формат по расширению файла: .json, .yaml или .yml.
+func FormatOf(fileName string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		return JSON, true
+	case ".yaml", ".yml":
+		return YAML, true
+	}
+	return 0, false
+}
+
+// Decode читает документ v из r, неизвестные поля считаются ошибкой.
+func Decode(r io.Reader, format Format, v interface{}) error {
+	switch format {
+	case JSON:
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		return dec.Decode(v)
+	case YAML:
+		dec := yaml.NewDecoder(r)
+		dec.KnownFields(true)
+		return dec.Decode(v)
+	}
+	return fmt.Errorf("unknown format %d", format)
+}
+
+// Encode пишет документ v в w.
+func Encode(w io.Writer, format Format, v interface{}) error {
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case YAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		err := enc.Encode(v)
+		if err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return fmt.Errorf("unknown format %d", format)
+}
+
+// Read читает и проверяет модель.
+func Read(r io.Reader, format Format) (*Document, error) {
+	var doc Document
+	err := Decode(r, format, &doc)
+	if err != nil {
+		return nil, err
+	}
+	err = doc.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// ReadFile читает модель из файла, формат по расширению.
+func ReadFile(fileName string) (*Document, error) {
+	format, ok := FormatOf(fileName)
+	if !ok {
+		return nil, fmt.Errorf("unknown format of %s", fileName)
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := Read(bytes.NewReader(data), format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	return doc, nil
+}
+
+// WriteFile пишет документ v в файл, формат по расширению.
+func WriteFile(fileName string, v interface{}) error {
+	format, ok := FormatOf(fileName)
+	if !ok {
+		return fmt.Errorf("unknown format of %s", fileName)
+	}
+	var buf bytes.Buffer
+	err := Encode(&buf, format, v)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, buf.Bytes(), 0644)
+}
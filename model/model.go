@@ -0,0 +1,321 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+
+	str "github.com/kenits/strength"
+)
+
+// Version текущая версия схемы документов.
+const Version = 1
+
+// Document модель сечения.
+type Document struct {
+	Version   int        `json:"version" yaml:"version"`
+	Project   string     `json:"project" yaml:"project"`
+	Name      string     `json:"name" yaml:"name"`
+	Age       float64    `json:"age" yaml:"age"`
+	Symmetry  bool       `json:"symmetry" yaml:"symmetry"`
+	Hogging   bool       `json:"hogging" yaml:"hogging"`
+	Moment    float64    `json:"moment" yaml:"moment"`
+	Accuracy  float64    `json:"accuracy" yaml:"accuracy"`
+	Points    []Point    `json:"points" yaml:"points"`
+	Material  Material   `json:"material" yaml:"material"`
+	Rigid     []Rigid    `json:"rigid" yaml:"rigid"`
+	Flex      []Flex     `json:"flex" yaml:"flex"`
+	LoadCases []LoadCase `json:"load_cases,omitempty" yaml:"load_cases,omitempty"`
+}
+
+// Point расчётная точка по высоте.
+type Point struct {
+	Height float64 `json:"height" yaml:"height"` // высота м
+	Strain float64 `json:"strain" yaml:"strain"` // допускаемое напряжение кН/см2
+}
+
+// Material материал сечения.
+type Material struct {
+	Name         string  `json:"name,omitempty" yaml:"name,omitempty"`
+	ElasticModul float64 `json:"elastic_modul" yaml:"elastic_modul"` // модуль упругости кПа
+}
+
+// Rigid жёсткая связь.
+type Rigid struct {
+	ID          int     `json:"id" yaml:"id"`
+	Name        string  `json:"name" yaml:"name"`
+	Area        float64 `json:"area" yaml:"area"`           // см2
+	Corrosion   float64 `json:"corrosion" yaml:"corrosion"` // см2/год
+	Height      float64 `json:"height" yaml:"height"`       // м
+	HalfBreadth float64 `json:"half_breadth,omitempty" yaml:"half_breadth,omitempty"`
+	Side        string  `json:"side,omitempty" yaml:"side,omitempty"`
+	Count       float64 `json:"count" yaml:"count"`
+}
+
+// Flex гибкая связь.
+type Flex struct {
+	ID          int     `json:"id" yaml:"id"`
+	Name        string  `json:"name" yaml:"name"`
+	Length      float64 `json:"length" yaml:"length"`       // см
+	Width       float64 `json:"width" yaml:"width"`         // см
+	Thickness   float64 `json:"thickness" yaml:"thickness"` // мм
+	Corrosion   float64 `json:"corrosion" yaml:"corrosion"` // мм/год
+	Height      float64 `json:"height" yaml:"height"`       // м
+	HalfBreadth float64 `json:"half_breadth,omitempty" yaml:"half_breadth,omitempty"`
+	Side        string  `json:"side,omitempty" yaml:"side,omitempty"`
+	Count       float64 `json:"count" yaml:"count"`
+	Pressure    float64 `json:"pressure,omitempty" yaml:"pressure,omitempty"` // кПа
+}
+
+// LoadCase случай загрузки.
+type LoadCase struct {
+	Name       string     `json:"name" yaml:"name"`
+	Draft      float64    `json:"draft" yaml:"draft"` // м
+	SeaDensity float64    `json:"sea_density,omitempty" yaml:"sea_density,omitempty"`
+	Wave       *WaveLoad  `json:"wave,omitempty" yaml:"wave,omitempty"`
+	Tanks      []Tank     `json:"tanks,omitempty" yaml:"tanks,omitempty"`
+	Exposure   []Exposure `json:"exposure,omitempty" yaml:"exposure,omitempty"`
+}
+
+// WaveLoad волновая нагрузка.
+type WaveLoad struct {
+	Length       float64 `json:"length" yaml:"length"` // м
+	Speed        float64 `json:"speed" yaml:"speed"`   // уз
+	Distribution float64 `json:"distribution" yaml:"distribution"`
+}
+
+// Tank цистерна.
+type Tank struct {
+	Name         string  `json:"name" yaml:"name"`
+	Filling      float64 `json:"filling" yaml:"filling"` // м
+	Density      float64 `json:"density" yaml:"density"` // т/м3
+	Overpressure float64 `json:"overpressure,omitempty" yaml:"overpressure,omitempty"`
+}
+
+// Exposure нагрузки на пластину.
+type Exposure struct {
+	Flex int    `json:"flex" yaml:"flex"` // номер гибкой связи
+	Sea  bool   `json:"sea,omitempty" yaml:"sea,omitempty"`
+	Tank string `json:"tank,omitempty" yaml:"tank,omitempty"`
+}
+
+// sides имена бортов в документе.
+var sides = map[str.Side]string{
+	str.SideDefault:   "",
+	str.SideBoth:      "both",
+	str.SideCentre:    "centre",
+	str.SidePort:      "port",
+	str.SideStarboard: "starboard",
+}
+
+// parseSide разбирает имя борта.
+func parseSide(name string) (str.Side, error) {
+	for key, val := range sides {
+		if val == name {
+			return key, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown side %q", name)
+}
+
+// New создаёт документ по данным расчёта.
+func New(baseData *str.BaseData, rigid map[int]str.Rigid, flex map[int]str.Flex, loadCases []str.LoadCase) *Document {
+	doc := Document{
+		Version:  Version,
+		Project:  baseData.Project,
+		Name:     baseData.Name,
+		Age:      baseData.Age,
+		Symmetry: baseData.Symmetry,
+		Hogging:  baseData.MomentFlag,
+		Moment:   baseData.Moment,
+		Accuracy: baseData.Accuracy,
+		Material: Material{ElasticModul: baseData.ElasticModul},
+	}
+	for key := range baseData.Height {
+		doc.Points = append(doc.Points, Point{Height: baseData.Height[key], Strain: baseData.Strain[key]})
+	}
+	for _, id := range str.RigidIDs(rigid) {
+		val := rigid[id]
+		doc.Rigid = append(doc.Rigid, Rigid{
+			ID:          val.ID,
+			Name:        val.Name,
+			Area:        val.AreaStart,
+			Corrosion:   val.Corrosion,
+			Height:      val.Height,
+			HalfBreadth: val.HalfBreadth,
+			Side:        sides[val.Side],
+			Count:       val.Count,
+		})
+	}
+	for _, id := range str.FlexIDs(flex) {
+		val := flex[id]
+		doc.Flex = append(doc.Flex, Flex{
+			ID:          val.ID,
+			Name:        val.Name,
+			Length:      val.Length,
+			Width:       val.Width,
+			Thickness:   val.ThicknessStart,
+			Corrosion:   val.Corrosion,
+			Height:      val.Height,
+			HalfBreadth: val.HalfBreadth,
+			Side:        sides[val.Side],
+			Count:       val.Count,
+			Pressure:    val.Pressure,
+		})
+	}
+	for key := range loadCases {
+		doc.LoadCases = append(doc.LoadCases, newLoadCase(&loadCases[key]))
+	}
+	return &doc
+}
+
+// newLoadCase переводит случай загрузки в документ, цистерны и нагрузки по возрастанию.
+func newLoadCase(lc *str.LoadCase) LoadCase {
+	rez := LoadCase{
+		Name:       lc.Name,
+		Draft:      lc.Draft,
+		SeaDensity: lc.SeaDensity,
+	}
+	if lc.Wave != nil {
+		rez.Wave = &WaveLoad{Length: lc.Wave.Length, Speed: lc.Wave.Speed, Distribution: lc.Wave.Distribution}
+	}
+	names := make([]string, 0, len(lc.Tanks))
+	for name := range lc.Tanks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t := lc.Tanks[name]
+		rez.Tanks = append(rez.Tanks, Tank{Name: name, Filling: t.Filling, Density: t.Density, Overpressure: t.Overpressure})
+	}
+	ids := make([]int, 0, len(lc.Exposure))
+	for id := range lc.Exposure {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		e := lc.Exposure[id]
+		rez.Exposure = append(rez.Exposure, Exposure{Flex: id, Sea: e.Sea, Tank: e.Tank})
+	}
+	return rez
+}
+
+// BaseData исходные данные расчёта.
+func (d *Document) BaseData() *str.BaseData {
+	rez := str.BaseData{
+		Project:      d.Project,
+		Name:         d.Name,
+		Age:          d.Age,
+		ElasticModul: d.Material.ElasticModul,
+		Symmetry:     d.Symmetry,
+		MomentFlag:   d.Hogging,
+		Moment:       d.Moment,
+		Accuracy:     d.Accuracy,
+	}
+	for _, val := range d.Points {
+		rez.Height = append(rez.Height, val.Height)
+		rez.Strain = append(rez.Strain, val.Strain)
+	}
+	return &rez
+}
+
+// Elements жёсткие и гибкие связи по номерам, площади на срок службы не посчитаны.
+func (d *Document) Elements() (map[int]str.Rigid, map[int]str.Flex, error) {
+	rigid := make(map[int]str.Rigid, len(d.Rigid))
+	for _, val := range d.Rigid {
+		if _, ok := rigid[val.ID]; ok {
+			return nil, nil, fmt.Errorf("duplicate rigid %d", val.ID)
+		}
+		side, err := parseSide(val.Side)
+		if err != nil {
+			return nil, nil, fmt.Errorf("rigid %d: %v", val.ID, err)
+		}
+		rigid[val.ID] = str.Rigid{
+			ID:          val.ID,
+			Name:        val.Name,
+			AreaStart:   val.Area,
+			Corrosion:   val.Corrosion,
+			Height:      val.Height,
+			HalfBreadth: val.HalfBreadth,
+			Side:        side,
+			Count:       val.Count,
+		}
+	}
+	flex := make(map[int]str.Flex, len(d.Flex))
+	for _, val := range d.Flex {
+		if _, ok := flex[val.ID]; ok {
+			return nil, nil, fmt.Errorf("duplicate flex %d", val.ID)
+		}
+		side, err := parseSide(val.Side)
+		if err != nil {
+			return nil, nil, fmt.Errorf("flex %d: %v", val.ID, err)
+		}
+		flex[val.ID] = str.Flex{
+			ID:             val.ID,
+			Name:           val.Name,
+			Length:         val.Length,
+			Width:          val.Width,
+			ThicknessStart: val.Thickness,
+			Corrosion:      val.Corrosion,
+			Height:         val.Height,
+			HalfBreadth:    val.HalfBreadth,
+			Side:           side,
+			Count:          val.Count,
+			Pressure:       val.Pressure,
+		}
+	}
+	return rigid, flex, nil
+}
+
+// LoadCase случай загрузки расчёта.
+func (c *LoadCase) LoadCase() (*str.LoadCase, error) {
+	rez := str.LoadCase{
+		Name:       c.Name,
+		Draft:      c.Draft,
+		SeaDensity: c.SeaDensity,
+		Tanks:      make(map[string]str.Tank, len(c.Tanks)),
+		Exposure:   make(map[int]str.Exposure, len(c.Exposure)),
+	}
+	if c.Wave != nil {
+		rez.Wave = &str.WaveLoad{Length: c.Wave.Length, Speed: c.Wave.Speed, Distribution: c.Wave.Distribution}
+	}
+	for _, val := range c.Tanks {
+		if _, ok := rez.Tanks[val.Name]; ok {
+			return nil, fmt.Errorf("load case %q: duplicate tank %q", c.Name, val.Name)
+		}
+		rez.Tanks[val.Name] = str.Tank{Name: val.Name, Filling: val.Filling, Density: val.Density, Overpressure: val.Overpressure}
+	}
+	for _, val := range c.Exposure {
+		if _, ok := rez.Exposure[val.Flex]; ok {
+			return nil, fmt.Errorf("load case %q: duplicate exposure of flex %d", c.Name, val.Flex)
+		}
+		rez.Exposure[val.Flex] = str.Exposure{Sea: val.Sea, Tank: val.Tank}
+	}
+	return &rez, nil
+}
+
+// Validate проверяет версию и согласованность документа.
+func (d *Document) Validate() error {
+	switch {
+	case d.Version == 0:
+		return fmt.Errorf("missing version")
+	case d.Version > Version:
+		return fmt.Errorf("unsupported version %d, want at most %d", d.Version, Version)
+	case len(d.Points) == 0:
+		return fmt.Errorf("missing points")
+	case d.Material.ElasticModul <= 0:
+		return fmt.Errorf("bad elastic modul %v", d.Material.ElasticModul)
+	case d.Accuracy <= 0:
+		return fmt.Errorf("bad accuracy %v", d.Accuracy)
+	}
+	_, _, err := d.Elements()
+	if err != nil {
+		return err
+	}
+	for key := range d.LoadCases {
+		_, err = d.LoadCases[key].LoadCase()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	str "github.com/kenits/strength"
+)
+
+func testModel() (*str.BaseData, map[int]str.Rigid, map[int]str.Flex, []str.LoadCase) {
+	base := str.BaseData{
+		Project:      "Проект",
+		Name:         "Мидель",
+		Age:          20,
+		Height:       []float64{0, 10},
+		Strain:       []float64{23.5, 23.5},
+		ElasticModul: 2.06e8,
+		Symmetry:     true,
+		Moment:       3e5,
+		Accuracy:     1e-3,
+	}
+	rigid := map[int]str.Rigid{
+		1: {ID: 1, Name: "Киль", AreaStart: 30, Corrosion: 0.1, Height: 0.1, Count: 10},
+		2: {ID: 2, Name: "Палуба", AreaStart: 30, Height: 9.9, HalfBreadth: 4, Side: str.SideBoth, Count: 10},
+	}
+	flex := map[int]str.Flex{
+		1: {ID: 1, Name: "Днище", Length: 240, Width: 60, ThicknessStart: 8, Count: 10},
+		2: {ID: 2, Name: "Борт", Length: 60, Width: 240, ThicknessStart: 8, Height: 5, Side: str.SideStarboard, Count: 4, Pressure: 50},
+	}
+	loadCases := []str.LoadCase{{
+		Name:     "Полный груз",
+		Draft:    8,
+		Wave:     &str.WaveLoad{Length: 150, Speed: 14, Distribution: 1},
+		Tanks:    map[string]str.Tank{"Танк": {Name: "Танк", Filling: 9, Density: 0.85}},
+		Exposure: map[int]str.Exposure{1: {Sea: true, Tank: "Танк"}},
+	}}
+	return &base, rigid, flex, loadCases
+}
+
+func TestDocument_roundTrip(t *testing.T) {
+	base, rigid, flex, loadCases := testModel()
+	for _, format := range []Format{JSON, YAML} {
+		var buf bytes.Buffer
+		err := Encode(&buf, format, New(base, rigid, flex, loadCases))
+		if err != nil {
+			t.Fatalf("Encode(%v) error = %v", format, err)
+		}
+		doc, err := Read(&buf, format)
+		if err != nil {
+			t.Fatalf("Read(%v) error = %v", format, err)
+		}
+		if got := doc.BaseData(); !reflect.DeepEqual(got, base) {
+			t.Errorf("Read(%v) base data = %+v, want %+v", format, got, base)
+		}
+		gotRigid, gotFlex, err := doc.Elements()
+		if err != nil {
+			t.Fatalf("Elements() error = %v", err)
+		}
+		if !reflect.DeepEqual(gotRigid, rigid) || !reflect.DeepEqual(gotFlex, flex) {
+			t.Errorf("Read(%v) elements = %+v %+v, want %+v %+v", format, gotRigid, gotFlex, rigid, flex)
+		}
+		if len(doc.LoadCases) != 1 {
+			t.Fatalf("Read(%v) load cases = %v", format, doc.LoadCases)
+		}
+		gotCase, err := doc.LoadCases[0].LoadCase()
+		if err != nil {
+			t.Fatalf("LoadCase() error = %v", err)
+		}
+		if !reflect.DeepEqual(*gotCase, loadCases[0]) {
+			t.Errorf("Read(%v) load case = %+v, want %+v", format, *gotCase, loadCases[0])
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	valid := `version: 1
+accuracy: 0.01
+points: [{height: 0, strain: 23.5}]
+material: {elastic_modul: 2.06e8}
+`
+	tests := []struct {
+		name    string
+		data    string
+		format  Format
+		wantErr string
+	}{
+		{"valid", valid, YAML, ""},
+		{"json", `{"version": 1, "accuracy": 0.01, "points": [{"height": 0, "strain": 23.5}], "material": {"elastic_modul": 2.06e8}}`, JSON, ""},
+		{"no version", strings.Replace(valid, "version: 1", "version: 0", 1), YAML, "missing version"},
+		{"new version", strings.Replace(valid, "version: 1", "version: 2", 1), YAML, "unsupported version"},
+		{"unknown field", valid + "moment_flag: true\n", YAML, "moment_flag"},
+		{"bad side", valid + "rigid: [{id: 1, side: left}]\n", YAML, "unknown side"},
+		{"duplicate flex", valid + "flex: [{id: 1}, {id: 1}]\n", YAML, "duplicate flex 1"},
+		{"duplicate tank", valid + "load_cases: [{name: a, tanks: [{name: t}, {name: t}]}]\n", YAML, "duplicate tank"},
+		{"no material", strings.Replace(valid, "2.06e8", "0", 1), YAML, "bad elastic modul"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.data), tt.format)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Read() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Read() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRezult_Add(t *testing.T) {
+	base, rigid, flex, _ := testModel()
+	str.CalcAllRigid(rigid, base.Age)
+	str.CalcAllFlex(flex, base.Age)
+	data, conv, err := str.CalculateWithOptions(base, rigid, flex, &str.Options{})
+	if err != nil {
+		t.Fatalf("CalculateWithOptions() error = %v", err)
+	}
+
+	rez := NewRezult(base)
+	rez.Add("", data, &conv)
+	c := rez.Cases[0]
+	if len(c.Iterations) != len(data) || c.Iterations[0].Plates != nil || len(c.Iterations[1].Plates) != len(data[1].Approx) {
+		t.Fatalf("Add() iterations = %d, want %d", len(c.Iterations), len(data))
+	}
+	if float64(c.Section.MomentOfInertia) != data.Last().MomentOfInertia || c.Convergence.Iterations != conv.Iterations {
+		t.Errorf("Add() case = %+v", c.Convergence)
+	}
+
+	for _, format := range []Format{JSON, YAML} {
+		var buf bytes.Buffer
+		err := Encode(&buf, format, rez)
+		if err != nil {
+			t.Fatalf("Encode(%v) error = %v", format, err)
+		}
+		var got Rezult
+		err = Decode(&buf, format, &got)
+		if err != nil {
+			t.Fatalf("Decode(%v) error = %v", format, err)
+		}
+		if !reflect.DeepEqual(got, *rez) {
+			t.Errorf("Decode(%v) = %+v, want %+v", format, got.Cases[0].Section, rez.Cases[0].Section)
+		}
+	}
+}
+
+func TestRezult_nonFinite(t *testing.T) {
+	// контрольная точка на нейтральной оси: момент сопротивления бесконечен
+	data := str.Iterations{{ID: 1, Rezult: str.Rezult{
+		CenterOfMass:        5,
+		MomentsOfResistance: []float64{1000, math.Inf(1)},
+		Heigth:              []float64{0, 5},
+		Strain:              []float64{math.Inf(-1), math.NaN()},
+	}}}
+	rez := NewRezult(&str.BaseData{})
+	rez.Add("", data, &str.Convergence{})
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, JSON, rez); err != nil {
+		t.Fatalf("Encode(JSON) error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `"+Inf"`) || !strings.Contains(buf.String(), `"-Inf"`) || !strings.Contains(buf.String(), `"NaN"`) {
+		t.Errorf("Encode(JSON) = %s, want non-finite values as strings", buf.String())
+	}
+	for _, format := range []Format{JSON, YAML} {
+		buf.Reset()
+		if err := Encode(&buf, format, rez); err != nil {
+			t.Fatalf("Encode(%v) error = %v", format, err)
+		}
+		var got Rezult
+		if err := Decode(&buf, format, &got); err != nil {
+			t.Fatalf("Decode(%v) error = %v", format, err)
+		}
+		s := got.Cases[0].Section
+		if s.MomentsOfResistance[0] != 1000 || !math.IsInf(float64(s.MomentsOfResistance[1]), 1) ||
+			!math.IsInf(float64(s.Strain[0]), -1) || !math.IsNaN(float64(s.Strain[1])) {
+			t.Errorf("Decode(%v) section = %+v", format, s)
+		}
+	}
+
+	var n Number
+	if err := json.Unmarshal([]byte(`"inf"`), &n); err == nil {
+		t.Errorf("Number.UnmarshalJSON() bad string error expected")
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   Format
+		wantOk bool
+	}{
+		{"model.json", JSON, true},
+		{"model.YAML", YAML, true},
+		{"dir/model.yml", YAML, true},
+		{"model.xlsx", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := FormatOf(tt.name)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("FormatOf(%q) = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
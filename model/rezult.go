@@ -0,0 +1,209 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	str "github.com/kenits/strength"
+)
+
+// Number число результатов. JSON не допускает бесконечности и NaN, поэтому в JSON они пишутся
+// строками "+Inf", "-Inf" и "NaN", например бесконечный момент сопротивления в точке на нейтральной оси.
+// В YAML они пишутся как .inf, -.inf и .nan.
+type Number float64
+
+// MarshalJSON пишет число, бесконечности и NaN строками.
+func (n Number) MarshalJSON() ([]byte, error) {
+	val := float64(n)
+	if math.IsInf(val, 0) || math.IsNaN(val) {
+		return json.Marshal(fmt.Sprint(val))
+	}
+	return json.Marshal(val)
+}
+
+// UnmarshalJSON читает число или строку "+Inf", "-Inf", "NaN".
+func (n *Number) UnmarshalJSON(data []byte) error {
+	var text string
+	if json.Unmarshal(data, &text) != nil {
+		return json.Unmarshal(data, (*float64)(n))
+	}
+	switch text {
+	case "+Inf":
+		*n = Number(math.Inf(1))
+	case "-Inf":
+		*n = Number(math.Inf(-1))
+	case "NaN":
+		*n = Number(math.NaN())
+	default:
+		return fmt.Errorf("bad number %q", text)
+	}
+	return nil
+}
+
+// numbers переводит срез чисел в числа результатов.
+func numbers(data []float64) []Number {
+	if data == nil {
+		return nil
+	}
+	rez := make([]Number, len(data))
+	for key, val := range data {
+		rez[key] = Number(val)
+	}
+	return rez
+}
+
+// Rezult результаты расчёта модели.
+type Rezult struct {
+	Version int    `json:"version" yaml:"version"`
+	Project string `json:"project" yaml:"project"`
+	Name    string `json:"name" yaml:"name"`
+	Cases   []Case `json:"cases" yaml:"cases"`
+}
+
+// Case результат расчёта по случаю загрузки.
+type Case struct {
+	LoadCase    string      `json:"load_case,omitempty" yaml:"load_case,omitempty"` // пусто если считалось без случая загрузки
+	Convergence Convergence `json:"convergence" yaml:"convergence"`
+	Section     Section     `json:"section" yaml:"section"` // результат последнего приближения
+	Iterations  []Iteration `json:"iterations" yaml:"iterations"`
+}
+
+// Convergence сходимость расчёта.
+type Convergence struct {
+	Acceleration string `json:"acceleration" yaml:"acceleration"`
+	Iterations   int    `json:"iterations" yaml:"iterations"`
+	Converged    bool   `json:"converged" yaml:"converged"`
+}
+
+// Section характеристики сечения, единицы как в strength.Rezult.
+type Section struct {
+	Area                       Number   `json:"area" yaml:"area"`
+	StaticMoment               Number   `json:"static_moment" yaml:"static_moment"`
+	CenterOfMass               Number   `json:"center_of_mass" yaml:"center_of_mass"`
+	MomentOfInertia            Number   `json:"moment_of_inertia" yaml:"moment_of_inertia"`
+	MomentsOfResistance        []Number `json:"moments_of_resistance" yaml:"moments_of_resistance"`
+	Moments                    []Number `json:"moments,omitempty" yaml:"moments,omitempty"`
+	Moment                     Number   `json:"moment,omitempty" yaml:"moment,omitempty"`
+	Strain                     []Number `json:"strain,omitempty" yaml:"strain,omitempty"`
+	AreaLoss                   Number   `json:"area_loss" yaml:"area_loss"`
+	StaticMomentLoss           Number   `json:"static_moment_loss" yaml:"static_moment_loss"`
+	MomentOfInertiaLoss        Number   `json:"moment_of_inertia_loss" yaml:"moment_of_inertia_loss"`
+	Height                     []Number `json:"height" yaml:"height"`
+	HalfBreadth                Number   `json:"half_breadth" yaml:"half_breadth"`
+	TransverseInertia          Number   `json:"transverse_inertia" yaml:"transverse_inertia"`
+	ProductOfInertia           Number   `json:"product_of_inertia" yaml:"product_of_inertia"`
+	NeutralAxisAngle           Number   `json:"neutral_axis_angle" yaml:"neutral_axis_angle"`
+	PrincipalAngle             Number   `json:"principal_angle" yaml:"principal_angle"`
+	PrincipalInertia           Number   `json:"principal_inertia" yaml:"principal_inertia"`
+	PrincipalTransverseInertia Number   `json:"principal_transverse_inertia" yaml:"principal_transverse_inertia"`
+}
+
+// Iteration приближение расчёта.
+type Iteration struct {
+	ID      int     `json:"id" yaml:"id"`
+	Section Section `json:"section" yaml:"section"`
+	Plates  []Plate `json:"plates,omitempty" yaml:"plates,omitempty"` // в первом приближении нет
+}
+
+// Plate редукция пластины в приближении, единицы как в strength.Approx.
+type Plate struct {
+	ID                  int    `json:"id" yaml:"id"`
+	Key                 int    `json:"key" yaml:"key"`
+	Reducing            Number `json:"reducing" yaml:"reducing"`
+	ReverseReducing     Number `json:"reverse_reducing" yaml:"reverse_reducing"`
+	ReducingArea        Number `json:"reducing_area" yaml:"reducing_area"`
+	AreaLoss            Number `json:"area_loss" yaml:"area_loss"`
+	Height              Number `json:"height" yaml:"height"`
+	HalfBreadth         Number `json:"half_breadth" yaml:"half_breadth"`
+	StaticMomentLoss    Number `json:"static_moment_loss" yaml:"static_moment_loss"`
+	MomentOfInertiaLoss Number `json:"moment_of_inertia_loss" yaml:"moment_of_inertia_loss"`
+	ActualStrain        Number `json:"actual_strain" yaml:"actual_strain"`
+	EulerianStrain      Number `json:"eulerian_strain" yaml:"eulerian_strain"`
+	ChainStrain         Number `json:"chain_strain" yaml:"chain_strain"`
+	Rho                 Number `json:"rho" yaml:"rho"`
+	Kappa               Number `json:"kappa" yaml:"kappa"`
+	StartCurvature      Number `json:"start_curvature" yaml:"start_curvature"`
+	PressCurvature      Number `json:"press_curvature" yaml:"press_curvature"`
+	X                   Number `json:"x" yaml:"x"`
+}
+
+// NewRezult создаёт пустой документ результатов по исходным данным.
+func NewRezult(baseData *str.BaseData) *Rezult {
+	return &Rezult{
+		Version: Version,
+		Project: baseData.Project,
+		Name:    baseData.Name,
+	}
+}
+
+// Add добавляет результат расчёта по случаю загрузки loadCase, пусто если без случая.
+func (r *Rezult) Add(loadCase string, data str.Iterations, conv *str.Convergence) {
+	c := Case{
+		LoadCase: loadCase,
+		Convergence: Convergence{
+			Acceleration: conv.Acceleration.String(),
+			Iterations:   conv.Iterations,
+			Converged:    conv.Converged,
+		},
+		Section:    newSection(data.Last()),
+		Iterations: make([]Iteration, 0, len(data)),
+	}
+	for _, val := range data {
+		it := Iteration{ID: val.ID, Section: newSection(val.Rezult)}
+		for key := range val.Approx {
+			it.Plates = append(it.Plates, newPlate(&val.Approx[key]))
+		}
+		c.Iterations = append(c.Iterations, it)
+	}
+	r.Cases = append(r.Cases, c)
+}
+
+// newSection переводит результат приближения в документ.
+func newSection(r str.Rezult) Section {
+	return Section{
+		Area:                       Number(r.Area),
+		StaticMoment:               Number(r.StaticMoment),
+		CenterOfMass:               Number(r.CenterOfMass),
+		MomentOfInertia:            Number(r.MomentOfInertia),
+		MomentsOfResistance:        numbers(r.MomentsOfResistance),
+		Moments:                    numbers(r.Moments),
+		Moment:                     Number(r.Moment),
+		Strain:                     numbers(r.Strain),
+		AreaLoss:                   Number(r.AreaLoss),
+		StaticMomentLoss:           Number(r.StaticMomentLoss),
+		MomentOfInertiaLoss:        Number(r.MomentOfInertiaLoss),
+		Height:                     numbers(r.Heigth),
+		HalfBreadth:                Number(r.HalfBreadth),
+		TransverseInertia:          Number(r.TransverseInertia),
+		ProductOfInertia:           Number(r.ProductOfInertia),
+		NeutralAxisAngle:           Number(r.NeutralAxisAngle),
+		PrincipalAngle:             Number(r.PrincipalAngle),
+		PrincipalInertia:           Number(r.PrincipalInertia),
+		PrincipalTransverseInertia: Number(r.PrincipalTransverseInertia),
+	}
+}
+
+// newPlate переводит приближение пластины в документ.
+func newPlate(a *str.Approx) Plate {
+	return Plate{
+		ID:                  a.ID,
+		Key:                 a.Key,
+		Reducing:            Number(a.Reducing),
+		ReverseReducing:     Number(a.ReverseReducing),
+		ReducingArea:        Number(a.ReducingArea),
+		AreaLoss:            Number(a.AreaLoss),
+		Height:              Number(a.Height),
+		HalfBreadth:         Number(a.HalfBreadth),
+		StaticMomentLoss:    Number(a.StaticMomentLoss),
+		MomentOfInertiaLoss: Number(a.MomentOfInertiaLoss),
+		ActualStrain:        Number(a.ActualStrain),
+		EulerianStrain:      Number(a.EulerianStrain),
+		ChainStrain:         Number(a.ChainStrain),
+		Rho:                 Number(a.Rho),
+		Kappa:               Number(a.Kappa),
+		StartCurvature:      Number(a.StartCurvature),
+		PressCurvature:      Number(a.PressCurvature),
+		X:                   Number(a.X),
+	}
+}
@@ -0,0 +1,348 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Column столбец таблицы связей.
+type Column struct {
+	Name     string   // имя поля в схеме
+	Aliases  []string // другие имена заголовка на русском и английском
+	Unit     string   // единица значения в модели, пусто для номеров, строк и количества
+	Required bool     // столбец обязателен
+}
+
+// RigidColumns столбцы таблицы жёстких связей.
+var RigidColumns = []Column{
+	{Name: "id", Aliases: []string{"№", "номер", "no"}, Required: true},
+	{Name: "name", Aliases: []string{"имя", "название", "наименование"}},
+	{Name: "area", Aliases: []string{"площадь"}, Unit: "cm2", Required: true},
+	{Name: "corrosion", Aliases: []string{"коррозия"}, Unit: "cm2/year"},
+	{Name: "height", Aliases: []string{"высота", "z"}, Unit: "m", Required: true},
+	{Name: "half_breadth", Aliases: []string{"полуширота", "y"}, Unit: "m"},
+	{Name: "side", Aliases: []string{"борт"}},
+	{Name: "count", Aliases: []string{"количество", "кол-во"}},
+}
+
+// FlexColumns столбцы таблицы гибких связей.
+var FlexColumns = []Column{
+	{Name: "id", Aliases: []string{"№", "номер", "no"}, Required: true},
+	{Name: "name", Aliases: []string{"имя", "название", "наименование"}},
+	{Name: "length", Aliases: []string{"длина", "длинна"}, Unit: "cm", Required: true},
+	{Name: "width", Aliases: []string{"ширина"}, Unit: "cm", Required: true},
+	{Name: "thickness", Aliases: []string{"толщина"}, Unit: "mm", Required: true},
+	{Name: "corrosion", Aliases: []string{"коррозия"}, Unit: "mm/year"},
+	{Name: "height", Aliases: []string{"высота", "z"}, Unit: "m", Required: true},
+	{Name: "half_breadth", Aliases: []string{"полуширота", "y"}, Unit: "m"},
+	{Name: "side", Aliases: []string{"борт"}},
+	{Name: "count", Aliases: []string{"количество", "кол-во"}},
+	{Name: "pressure", Aliases: []string{"давление", "нагрузка"}, Unit: "kPa"},
+}
+
+// PointColumns столбцы таблицы расчётных точек.
+var PointColumns = []Column{
+	{Name: "height", Aliases: []string{"высота", "z"}, Unit: "m", Required: true},
+	{Name: "strain", Aliases: []string{"допускаемое напряжение", "допускаемые напряжения", "напряжение"}, Unit: "kN/cm2", Required: true},
+}
+
+// normalize приводит имя заголовка к виду для сравнения.
+func normalize(name string) string {
+	s := strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("ё", "е", " ", "", "_", "", "-", "").Replace(s)
+}
+
+// match true если заголовок подходит столбцу.
+func (c *Column) match(name string) bool {
+	s := normalize(name)
+	if s == normalize(c.Name) {
+		return true
+	}
+	for _, val := range c.Aliases {
+		if s == normalize(val) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match true если заголовок с единицей или без неё подходит столбцу.
+func (c *Column) Match(header string) bool {
+	name, _ := splitHeader(header)
+	return c.match(header) || c.match(name)
+}
+
+// Header расположение столбцов таблицы, найденное по заголовку.
+type Header struct {
+	Index    map[string]int     // номер столбца таблицы с 0 по имени поля
+	Scale    map[string]float64 // множитель перевода в единицы модели по имени поля
+	required map[string]bool    // обязательные поля
+}
+
+// ParseHeader находит столбцы по строке заголовка.
+// Единица в заголовке ("площадь, мм2", "area [cm2]") переводится в единицу модели,
+// без единицы значение считается в единицах модели. Неизвестные столбцы пропускаются.
+// Ошибки InputErrors с местом в строке 1: повторы и неверные единицы столбцов, недостающие столбцы.
+func ParseHeader(record []string, columns []Column) (*Header, error) {
+	h := Header{
+		Index:    make(map[string]int, len(columns)),
+		Scale:    make(map[string]float64, len(columns)),
+		required: make(map[string]bool, len(columns)),
+	}
+	var errs InputErrors
+	for key, val := range record {
+		name, unit := splitHeader(val)
+		for i := range columns {
+			c := &columns[i]
+			if !c.match(name) && !c.match(val) {
+				continue
+			}
+			if _, ok := h.Index[c.Name]; ok {
+				errs = append(errs, InputError{Row: 1, Column: key + 1, Field: c.Name, Value: val, Expected: "unique column"})
+				break
+			}
+			scale := 1.0
+			if unit != "" && c.match(name) {
+				var err error
+				scale, err = unitScale(unit, c.Unit)
+				if c.Unit == "" || err != nil {
+					expected := "column without unit"
+					if c.Unit != "" {
+						expected = "unit compatible with " + c.Unit
+					}
+					errs = append(errs, InputError{Row: 1, Column: key + 1, Field: c.Name, Value: val, Expected: expected})
+					h.Index[c.Name] = key
+					break
+				}
+			}
+			h.Index[c.Name] = key
+			h.Scale[c.Name] = scale
+			break
+		}
+	}
+	for _, c := range columns {
+		h.required[c.Name] = c.Required
+		if _, ok := h.Index[c.Name]; c.Required && !ok {
+			errs = append(errs, InputError{Row: 1, Field: c.Name, Expected: c.describe("column")})
+		}
+	}
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return &h, nil
+}
+
+// describe описание столбца или подписи kind для сообщений об ошибках.
+func (c *Column) describe(kind string) string {
+	if len(c.Aliases) == 0 {
+		return fmt.Sprintf("%s %q", kind, c.Name)
+	}
+	return fmt.Sprintf("%s %q (%s)", kind, c.Name, strings.Join(c.Aliases, ", "))
+}
+
+// Missing ошибка недостающей подписи kind на листе sheet.
+func (c *Column) Missing(sheet, kind string) InputError {
+	return InputError{Sheet: sheet, Field: c.Name, Expected: c.describe(kind)}
+}
+
+// tableRow строка таблицы с разбором значений по именам полей.
+type tableRow struct {
+	header *Header
+	record []string
+	line   int          // номер строки в таблице с 1
+	errs   *InputErrors // ошибки значений
+}
+
+// text строка поля, пусто если столбца нет.
+func (r *tableRow) text(name string) string {
+	key, ok := r.header.Index[name]
+	if !ok || key >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[key])
+}
+
+// fail добавляет ошибку значения поля.
+func (r *tableRow) fail(name, expected string) {
+	*r.errs = append(*r.errs, InputError{
+		Row:      r.line,
+		Column:   r.header.Index[name] + 1,
+		Field:    name,
+		Value:    r.text(name),
+		Expected: expected,
+	})
+}
+
+// float число поля в единицах модели, def если необязательное поле пустое.
+// Допускается десятичная запятая.
+func (r *tableRow) float(name string, def float64) float64 {
+	s := r.text(name)
+	if s == "" {
+		if r.header.required[name] {
+			r.fail(name, "number")
+		}
+		return def
+	}
+	val, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil {
+		r.fail(name, "number")
+		return def
+	}
+	return val * r.header.Scale[name]
+}
+
+// int целое поле.
+func (r *tableRow) int(name string) int {
+	val, err := strconv.Atoi(r.text(name))
+	if err != nil {
+		r.fail(name, "integer")
+	}
+	return val
+}
+
+// id номер связи, повтор номера в таблице ошибка.
+func (r *tableRow) id(ids map[int]bool) int {
+	val, err := strconv.Atoi(r.text("id"))
+	if err != nil {
+		r.fail("id", "integer")
+		return val
+	}
+	if ids[val] {
+		r.fail("id", "unique id")
+	}
+	ids[val] = true
+	return val
+}
+
+// side борт поля, допускаются русские названия.
+func (r *tableRow) side(name string) string {
+	s := strings.ToLower(r.text(name))
+	switch s {
+	case "оба":
+		return "both"
+	case "дп":
+		return "centre"
+	case "левый", "лб":
+		return "port"
+	case "правый", "пб":
+		return "starboard"
+	}
+	if _, err := parseSide(s); err != nil {
+		r.fail(name, "side (both, centre, port, starboard)")
+	}
+	return s
+}
+
+// empty true если в строке нет значений.
+func empty(record []string) bool {
+	for _, val := range record {
+		if strings.TrimSpace(val) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// RigidTable разбирает таблицу жёстких связей: первая строка заголовок, пустые строки пропускаются.
+// Если количество не задано, принимается 1. Ошибки всех строк, в том числе повторы номеров, собираются в InputErrors.
+func RigidTable(records [][]string) ([]Rigid, error) {
+	if len(records) == 0 {
+		return nil, InputErrors{{Expected: "header"}}
+	}
+	h, err := ParseHeader(records[0], RigidColumns)
+	if err != nil {
+		return nil, err
+	}
+	var errs InputErrors
+	ids := make(map[int]bool, len(records)-1)
+	rez := make([]Rigid, 0, len(records)-1)
+	for key, record := range records[1:] {
+		if empty(record) {
+			continue
+		}
+		row := tableRow{header: h, record: record, line: key + 2, errs: &errs}
+		val := Rigid{
+			ID:          row.id(ids),
+			Name:        row.text("name"),
+			Area:        row.float("area", 0),
+			Corrosion:   row.float("corrosion", 0),
+			Height:      row.float("height", 0),
+			HalfBreadth: row.float("half_breadth", 0),
+			Side:        row.side("side"),
+			Count:       row.float("count", 1),
+		}
+		rez = append(rez, val)
+	}
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return rez, nil
+}
+
+// PointTable разбирает таблицу расчётных точек: первая строка заголовок, пустые строки пропускаются.
+func PointTable(records [][]string) ([]Point, error) {
+	if len(records) == 0 {
+		return nil, InputErrors{{Expected: "header"}}
+	}
+	h, err := ParseHeader(records[0], PointColumns)
+	if err != nil {
+		return nil, err
+	}
+	var errs InputErrors
+	rez := make([]Point, 0, len(records)-1)
+	for key, record := range records[1:] {
+		if empty(record) {
+			continue
+		}
+		row := tableRow{header: h, record: record, line: key + 2, errs: &errs}
+		val := Point{
+			Height: row.float("height", 0),
+			Strain: row.float("strain", 0),
+		}
+		rez = append(rez, val)
+	}
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return rez, nil
+}
+
+// FlexTable разбирает таблицу гибких связей: первая строка заголовок, пустые строки пропускаются.
+// Если количество не задано, принимается 1. Ошибки всех строк, в том числе повторы номеров, собираются в InputErrors.
+func FlexTable(records [][]string) ([]Flex, error) {
+	if len(records) == 0 {
+		return nil, InputErrors{{Expected: "header"}}
+	}
+	h, err := ParseHeader(records[0], FlexColumns)
+	if err != nil {
+		return nil, err
+	}
+	var errs InputErrors
+	ids := make(map[int]bool, len(records)-1)
+	rez := make([]Flex, 0, len(records)-1)
+	for key, record := range records[1:] {
+		if empty(record) {
+			continue
+		}
+		row := tableRow{header: h, record: record, line: key + 2, errs: &errs}
+		val := Flex{
+			ID:          row.id(ids),
+			Name:        row.text("name"),
+			Length:      row.float("length", 0),
+			Width:       row.float("width", 0),
+			Thickness:   row.float("thickness", 0),
+			Corrosion:   row.float("corrosion", 0),
+			Height:      row.float("height", 0),
+			HalfBreadth: row.float("half_breadth", 0),
+			Side:        row.side("side"),
+			Count:       row.float("count", 1),
+			Pressure:    row.float("pressure", 0),
+		}
+		rez = append(rez, val)
+	}
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return rez, nil
+}
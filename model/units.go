@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// unit единица измерения.
+type unit struct {
+	dim    string  // размерность
+	factor float64 // множитель перевода в СИ
+}
+
+// units единицы по обозначениям в нижнем регистре.
+var units = map[string]unit{
+	"m":   {"length", 1},
+	"м":   {"length", 1},
+	"cm":  {"length", 1e-2},
+	"см":  {"length", 1e-2},
+	"mm":  {"length", 1e-3},
+	"мм":  {"length", 1e-3},
+	"m2":  {"area", 1},
+	"м2":  {"area", 1},
+	"cm2": {"area", 1e-4},
+	"см2": {"area", 1e-4},
+	"mm2": {"area", 1e-6},
+	"мм2": {"area", 1e-6},
+	"pa":  {"pressure", 1},
+	"па":  {"pressure", 1},
+	"kpa": {"pressure", 1e3},
+	"кпа": {"pressure", 1e3},
+	"mpa": {"pressure", 1e6},
+	"мпа": {"pressure", 1e6},
+
+	"kn/cm2": {"pressure", 1e7},
+	"кн/см2": {"pressure", 1e7},
+}
+
+// years обозначения года в единицах скорости коррозии.
+var years = []string{"year", "yr", "y", "год", "г"}
+
+// parseUnit разбирает обозначение единицы, в том числе в год: "мм/год", "cm2/year".
+func parseUnit(name string) (unit, error) {
+	s := strings.ToLower(strings.TrimSpace(name))
+	s = strings.NewReplacer("²", "2", "^2", "2", " ", "").Replace(s)
+	if rez, ok := units[s]; ok {
+		return rez, nil
+	}
+	rate := false
+	if pos := strings.Index(s, "/"); pos >= 0 {
+		for _, val := range years {
+			if s[pos+1:] == val {
+				rate = true
+			}
+		}
+		if !rate {
+			return unit{}, fmt.Errorf("unknown unit %q", name)
+		}
+		s = s[:pos]
+	}
+	rez, ok := units[s]
+	if !ok {
+		return unit{}, fmt.Errorf("unknown unit %q", name)
+	}
+	if rate {
+		rez.dim += "/year"
+	}
+	return rez, nil
+}
+
+// unitScale множитель перевода из единицы from в единицу to.
+func unitScale(from, to string) (float64, error) {
+	f, err := parseUnit(from)
+	if err != nil {
+		return 0, err
+	}
+	t, err := parseUnit(to)
+	if err != nil {
+		return 0, err
+	}
+	if f.dim != t.dim {
+		return 0, fmt.Errorf("unit %q incompatible with %q", from, to)
+	}
+	return f.factor / t.factor, nil
+}
+
+// splitHeader отделяет имя столбца от единицы: "area [cm2]", "area (cm2)", "Площадь, см2".
+func splitHeader(header string) (name, unit string) {
+	s := strings.TrimSpace(header)
+	for _, pair := range [][2]string{{"[", "]"}, {"(", ")"}} {
+		if strings.HasSuffix(s, pair[1]) {
+			if pos := strings.LastIndex(s, pair[0]); pos >= 0 {
+				return strings.TrimSpace(s[:pos]), strings.TrimSpace(s[pos+1 : len(s)-1])
+			}
+		}
+	}
+	if pos := strings.LastIndex(s, ","); pos >= 0 {
+		return strings.TrimSpace(s[:pos]), strings.TrimSpace(s[pos+1:])
+	}
+	return s, ""
+}
@@ -0,0 +1,145 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+)
+
+// Gravity ускорение свободного падения м/с2.
+const Gravity = 9.81
+
+// SeaDensity плотность морской воды т/м3.
+const SeaDensity = 1.025
+
+// WaveLoad параметры волновой нагрузки по правилам РС (ч. II, 1.3.2.2)
+// для пластин борта и днища.
+type WaveLoad struct {
+	Length       float64 // расчётная длина судна м
+	Speed        float64 // скорость хода уз
+	Distribution float64 // коэффициент распределения по длине a_x для района сечения
+}
+
+// waveCoefficient волновой коэффициент c_w.
+func (w *WaveLoad) waveCoefficient() float64 {
+	switch {
+	case w.Length <= 90:
+		return 0.0856 * w.Length
+	case w.Length < 300:
+		return 10.75 - math.Pow((300-w.Length)/100, 1.5)
+	case w.Length <= 350:
+		return 10.75
+	}
+	return 10.75 - math.Pow((w.Length-350)/150, 1.5)
+}
+
+// pressure динамическое давление на высоте height при осадке draft кПа.
+// Ниже ватерлинии давление убывает до днища, выше ватерлинии до нуля.
+func (w *WaveLoad) pressure(height, draft float64) float64 {
+	cw := w.waveCoefficient()
+	av := 0.8*w.Speed/math.Sqrt(w.Length) + 1
+	p0 := 5 * cw * av * w.Distribution
+	if height <= draft {
+		return p0 - 1.5*cw*(draft-height)/draft
+	}
+	return math.Max(p0-7.5*w.Distribution*(height-draft), 0)
+}
+
+// Tank цистерна или грузовой танк.
+type Tank struct {
+	Name         string  // имя
+	Filling      float64 // уровень налива относительно ОП м
+	Density      float64 // плотность груза т/м3
+	Overpressure float64 // избыточное давление над грузом (клапан) кПа
+}
+
+// pressure давление груза на высоте height кПа.
+func (t *Tank) pressure(height float64) float64 {
+	rez := t.Overpressure + t.Density*Gravity*math.Max(t.Filling-height, 0)
+	return rez
+}
+
+// Exposure нагрузки действующие на пластину.
+type Exposure struct {
+	Sea  bool   // пластина наружной обшивки
+	Tank string // имя цистерны с которой граничит пластина, пусто если нет
+}
+
+// LoadCase случай загрузки судна.
+type LoadCase struct {
+	Name       string           // имя случая
+	Draft      float64          // осадка в районе сечения м
+	SeaDensity float64          // плотность забортной воды т/м3, если 0 то SeaDensity
+	Wave       *WaveLoad        // волновая нагрузка, nil на тихой воде
+	Tanks      map[string]Tank  // цистерны по именам
+	Exposure   map[int]Exposure // нагрузки по номерам гибких связей
+}
+
+// seaPressure давление забортной воды на высоте height кПа.
+func (c *LoadCase) seaPressure(height float64) float64 {
+	density := c.SeaDensity
+	if density == 0 {
+		density = SeaDensity
+	}
+	rez := density * Gravity * math.Max(c.Draft-height, 0)
+	if c.Wave != nil {
+		rez += c.Wave.pressure(height, c.Draft)
+	}
+	return rez
+}
+
+// Pressure расчётное давление на пластину кПа.
+// Забортное давление и давление груза рассматриваются раздельно, принимается большее из них.
+func (c *LoadCase) Pressure(plate *Flex) (float64, error) {
+	e, ok := c.Exposure[plate.ID]
+	if !ok {
+		return plate.Pressure, nil
+	}
+	var rez float64
+	if e.Sea {
+		rez = c.seaPressure(plate.Height)
+	}
+	if e.Tank != "" {
+		t, ok := c.Tanks[e.Tank]
+		if !ok {
+			return 0, fmt.Errorf("missing tank %q of flex %d", e.Tank, plate.ID)
+		}
+		rez = math.Max(rez, t.pressure(plate.Height))
+	}
+	return rez, nil
+}
+
+// ApplyPressure назначает давление пластинам по случаю загрузки.
+// Пластинам без заданных нагрузок давление оставляется введённым вручную.
+func ApplyPressure(loadCase *LoadCase, flex map[int]Flex) error {
+	if loadCase.Draft <= 0 && loadCase.Wave != nil {
+		return fmt.Errorf("bad draft %v", loadCase.Draft)
+	}
+	if w := loadCase.Wave; w != nil && (w.Length <= 0 || w.Distribution <= 0) {
+		return fmt.Errorf("bad wave load %+v", *w)
+	}
+	for id := range loadCase.Exposure {
+		if _, ok := flex[id]; !ok {
+			return fmt.Errorf("exposure of missing flex %d", id)
+		}
+	}
+	for key, val := range flex {
+		p, err := loadCase.Pressure(&val)
+		if err != nil {
+			return err
+		}
+		val.Pressure = p
+		flex[key] = val
+	}
+	return nil
+}
+
+// CalculateLoadCase считает сечение при давлениях случая загрузки.
+// Исходные гибкие связи не меняются, поэтому по одним данным можно считать несколько случаев.
+func CalculateLoadCase(baseData *BaseData, loadCase *LoadCase, rigid map[int]Rigid, flex map[int]Flex) (Iterations, error) {
+	data := copyFlex(flex)
+	err := ApplyPressure(loadCase, data)
+	if err != nil {
+		return nil, err
+	}
+	return Calculate(baseData, rigid, data), nil
+}
@@ -0,0 +1,95 @@
+package strength
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWaveLoad_waveCoefficient(t *testing.T) {
+	tests := []struct {
+		name   string
+		length float64
+		want   float64
+	}{
+		{name: "short", length: 90, want: 7.704},
+		{name: "medium", length: 200, want: 9.75},
+		{name: "long", length: 320, want: 10.75},
+		{name: "very long", length: 500, want: 9.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := WaveLoad{Length: tt.length}
+			if got := w.waveCoefficient(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("WaveLoad.waveCoefficient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCase_Pressure(t *testing.T) {
+	wave := WaveLoad{Length: 200, Speed: 15, Distribution: 1}
+	p0 := 5 * 9.75 * (0.8*15/math.Sqrt(200) + 1)
+	lc := LoadCase{
+		Draft: 10,
+		Tanks: map[string]Tank{
+			"балласт": {Filling: 12, Density: 1},
+			"пустой":  {Overpressure: 25},
+		},
+		Exposure: map[int]Exposure{
+			1: {Sea: true},
+			2: {Sea: true, Tank: "балласт"},
+			3: {Tank: "пустой"},
+			4: {Sea: true},
+			5: {Tank: "нет такого"},
+		},
+	}
+	tests := []struct {
+		name    string
+		plate   Flex
+		wave    *WaveLoad
+		want    float64
+		wantErr bool
+	}{
+		{name: "sea", plate: Flex{ID: 1, Height: 2}, want: 1.025 * 9.81 * 8},
+		{name: "tank greater than sea", plate: Flex{ID: 2, Height: 2}, want: 98.1},
+		{name: "overpressure", plate: Flex{ID: 3, Height: 2}, want: 25},
+		{name: "above waterline", plate: Flex{ID: 4, Height: 12}, want: 0},
+		{name: "wave at waterline", plate: Flex{ID: 4, Height: 10}, wave: &wave, want: p0},
+		{name: "wave at bottom", plate: Flex{ID: 1, Height: 0}, wave: &wave, want: 1.025*9.81*10 + p0 - 1.5*9.75},
+		{name: "manual", plate: Flex{ID: 6, Height: 2, Pressure: 40}, want: 40},
+		{name: "missing tank", plate: Flex{ID: 5, Height: 2}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := lc
+			c.Wave = tt.wave
+			got, err := c.Pressure(&tt.plate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadCase.Pressure() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("LoadCase.Pressure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPressure(t *testing.T) {
+	flex := map[int]Flex{
+		1: {ID: 1, Height: 2},
+		2: {ID: 2, Height: 2, Pressure: 40},
+	}
+	lc := LoadCase{Draft: 10, Exposure: map[int]Exposure{1: {Sea: true}}}
+	if err := ApplyPressure(&lc, flex); err != nil {
+		t.Fatalf("ApplyPressure() error = %v", err)
+	}
+	if math.Abs(flex[1].Pressure-1.025*9.81*8) > 1e-9 || flex[2].Pressure != 40 {
+		t.Errorf("ApplyPressure() = %v, %v", flex[1].Pressure, flex[2].Pressure)
+	}
+
+	lc.Exposure[3] = Exposure{Sea: true}
+	if err := ApplyPressure(&lc, flex); err == nil {
+		t.Errorf("ApplyPressure() missing flex error = nil")
+	}
+}
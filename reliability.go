@@ -0,0 +1,298 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// DistributionKind вид распределения случайной величины.
+type DistributionKind int
+
+const (
+	// Deterministic неслучайная величина равная Mean.
+	Deterministic DistributionKind = iota
+	// Normal нормальное распределение.
+	Normal
+	// LogNormal логнормальное распределение с заданными средним и стандартом.
+	LogNormal
+	// Gumbel распределение экстремальных значений I типа (максимумов).
+	Gumbel
+	// Uniform равномерное распределение на Mean±StdDev*sqrt(3).
+	Uniform
+)
+
+// Distribution распределение случайной величины, задаётся средним и стандартом.
+type Distribution struct {
+	Kind   DistributionKind // вид распределения
+	Mean   float64          // среднее
+	StdDev float64          // стандарт
+}
+
+// Sample выдаёт случайное значение из распределения.
+func (d *Distribution) Sample(rnd *rand.Rand) float64 {
+	switch d.Kind {
+	case Normal:
+		return d.Mean + d.StdDev*rnd.NormFloat64()
+	case LogNormal:
+		return d.FromNormal(rnd.NormFloat64())
+	case Gumbel:
+		return d.FromNormal(rnd.NormFloat64())
+	case Uniform:
+		return d.Mean + d.StdDev*math.Sqrt(3)*(2*rnd.Float64()-1)
+	}
+	return d.Mean
+}
+
+// FromNormal преобразует стандартную нормальную величину u в величину с распределением d
+// (преобразование Розенблатта для одной величины).
+func (d *Distribution) FromNormal(u float64) float64 {
+	switch d.Kind {
+	case Normal:
+		return d.Mean + d.StdDev*u
+	case LogNormal:
+		zeta := math.Sqrt(math.Log(1 + math.Pow(d.StdDev/d.Mean, 2)))
+		lambda := math.Log(d.Mean) - zeta*zeta/2
+		return math.Exp(lambda + zeta*u)
+	case Gumbel:
+		alpha := math.Pi / (math.Sqrt(6) * d.StdDev)
+		mode := d.Mean - 0.5772156649015329/alpha
+		p := normalCDF(u)
+		return mode - math.Log(-math.Log(p))/alpha
+	case Uniform:
+		half := d.StdDev * math.Sqrt(3)
+		return d.Mean - half + 2*half*normalCDF(u)
+	}
+	return d.Mean
+}
+
+// normalCDF функция стандартного нормального распределения.
+func normalCDF(u float64) float64 {
+	return 0.5 * math.Erfc(-u/math.Sqrt2)
+}
+
+// normalInverse обратная функция стандартного нормального распределения.
+func normalInverse(p float64) float64 {
+	return -math.Sqrt2 * math.Erfcinv(2*p)
+}
+
+// ReliabilityModel случайные величины модели надёжности корпуса.
+// Для связей не указанных в картах параметры считаются неслучайными.
+// Случайные величины толщин и площадей задаются множителями к построечным значениям со средним 1.
+type ReliabilityModel struct {
+	Thickness      map[int]Distribution // множитель построечной толщины гибких связей
+	Area           map[int]Distribution // множитель построечной площади жёстких связей
+	FlexCorrosion  map[int]Distribution // множитель скорости коррозии гибких связей
+	RigidCorrosion map[int]Distribution // множитель скорости коррозии жёстких связей
+	Yield          Distribution         // множитель допускаемых напряжений в контрольных точках
+	StillWater     Distribution         // момент на тихой воде кН*м
+	Wave           Distribution         // волновой момент кН*м
+	Samples        int                  // количество испытаний
+	Seed           int64                // начальное значение генератора для воспроизводимости
+	Concurrency    int                  // количество параллельных расчётов, если 0 то по числу процессоров
+}
+
+// MonteCarlo результаты статистического моделирования.
+type MonteCarlo struct {
+	Capacity           []float64 // предельные моменты по испытаниям кН*м, NaN если расчёт не сошёлся
+	Load               []float64 // действующие моменты по испытаниям кН*м
+	CapacityMean       float64   // среднее предельного момента по сошедшимся испытаниям кН*м
+	CapacityStdDev     float64   // стандарт предельного момента по сошедшимся испытаниям кН*м
+	Failures           int       // количество отказов
+	Unconverged        int       // количество испытаний, расчёт которых не сошёлся, они считаются отказами
+	FailureProbability float64   // вероятность отказа
+	ReliabilityIndex   float64   // индекс надёжности, бесконечность если отказов не было
+}
+
+// mcSample одно испытание: случайные величины разыгрываются заранее в общем генераторе,
+// чтобы результат не зависел от порядка выполнения параллельных расчётов.
+type mcSample struct {
+	thickness      map[int]float64
+	area           map[int]float64
+	flexCorrosion  map[int]float64
+	rigidCorrosion map[int]float64
+	yield          float64
+	load           float64
+}
+
+// newSample создаёт испытание с пустыми множителями связей.
+func newSample(rigid, flex int) mcSample {
+	return mcSample{
+		thickness:      make(map[int]float64, flex),
+		area:           make(map[int]float64, rigid),
+		flexCorrosion:  make(map[int]float64, flex),
+		rigidCorrosion: make(map[int]float64, rigid),
+	}
+}
+
+// clone копирует испытание вместе с картами множителей.
+func (s *mcSample) clone() mcSample {
+	rez := newSample(len(s.area), len(s.thickness))
+	for key, val := range s.area {
+		rez.area[key] = val
+	}
+	for key, val := range s.rigidCorrosion {
+		rez.rigidCorrosion[key] = val
+	}
+	for key, val := range s.thickness {
+		rez.thickness[key] = val
+	}
+	for key, val := range s.flexCorrosion {
+		rez.flexCorrosion[key] = val
+	}
+	rez.yield = s.yield
+	rez.load = s.load
+	return rez
+}
+
+// sampleMultiplier разыгрывает множитель, отрицательные значения нормального распределения
+// дали бы отрицательные площадь, толщину или коррозию, поэтому они заменяются нулём.
+func sampleMultiplier(d Distribution, rnd *rand.Rand) float64 {
+	return math.Max(d.Sample(rnd), 0)
+}
+
+// drawSample разыгрывает одно испытание.
+func (m *ReliabilityModel) drawSample(rnd *rand.Rand, rigidIDs, flexIDs []int) mcSample {
+	s := newSample(len(rigidIDs), len(flexIDs))
+	for _, id := range rigidIDs {
+		s.area[id] = sampleMultiplier(m.Area[id], rnd)
+		s.rigidCorrosion[id] = sampleMultiplier(m.RigidCorrosion[id], rnd)
+	}
+	for _, id := range flexIDs {
+		s.thickness[id] = sampleMultiplier(m.Thickness[id], rnd)
+		s.flexCorrosion[id] = sampleMultiplier(m.FlexCorrosion[id], rnd)
+	}
+	s.yield = sampleMultiplier(m.Yield, rnd)
+	s.load = m.StillWater.Sample(rnd) + m.Wave.Sample(rnd)
+	return s
+}
+
+// defaultMultiplier возвращает неслучайный единичный множитель если распределение не задано.
+func defaultMultiplier(d Distribution) Distribution {
+	if d.Kind == Deterministic && d.Mean == 0 {
+		d.Mean = 1
+	}
+	return d
+}
+
+// sampleCapacity считает предельный момент при множителях испытания, NaN если расчёт не сошёлся.
+func sampleCapacity(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex, s *mcSample) float64 {
+	data := *baseData
+	data.Moment = 0
+	data.Strain = make([]float64, len(baseData.Strain))
+	for key, val := range baseData.Strain {
+		data.Strain[key] = val * s.yield
+	}
+	newRigid := copyRigid(rigid)
+	for key, val := range newRigid {
+		val.AreaStart *= s.area[key]
+		val.Corrosion *= s.rigidCorrosion[key]
+		val.calc(data.Age)
+		newRigid[key] = val
+	}
+	newFlex := copyFlex(flex)
+	for key, val := range newFlex {
+		val.ThicknessStart *= s.thickness[key]
+		val.Corrosion *= s.flexCorrosion[key]
+		val.calc(data.Age)
+		newFlex[key] = val
+	}
+	rez, ok := calculateLimited(&data, newRigid, newFlex)
+	if !ok {
+		return math.NaN()
+	}
+	return rez.Moment
+}
+
+// RunMonteCarlo оценивает вероятность отказа корпуса статистическим моделированием.
+// Отказ наступает когда суммарный момент на тихой воде и на волнении превышает предельный момент.
+// Построечные размеры и скорости коррозии берутся из связей, износ считается на срок службы baseData.Age.
+// Испытания считаются параллельно, результат воспроизводится при одинаковом Seed.
+func RunMonteCarlo(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex, model *ReliabilityModel) (*MonteCarlo, error) {
+	if model.Samples <= 0 {
+		return nil, fmt.Errorf("bad number of samples %d", model.Samples)
+	}
+	workers := model.Concurrency
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	m := *model
+	m.Yield = defaultMultiplier(m.Yield)
+	m.Thickness = make(map[int]Distribution, len(flex))
+	m.FlexCorrosion = make(map[int]Distribution, len(flex))
+	m.Area = make(map[int]Distribution, len(rigid))
+	m.RigidCorrosion = make(map[int]Distribution, len(rigid))
+	for id := range flex {
+		m.Thickness[id] = defaultMultiplier(model.Thickness[id])
+		m.FlexCorrosion[id] = defaultMultiplier(model.FlexCorrosion[id])
+	}
+	for id := range rigid {
+		m.Area[id] = defaultMultiplier(model.Area[id])
+		m.RigidCorrosion[id] = defaultMultiplier(model.RigidCorrosion[id])
+	}
+
+	rigidIDs := RigidIDs(rigid)
+	flexIDs := FlexIDs(flex)
+	rnd := rand.New(rand.NewSource(m.Seed))
+	samples := make([]mcSample, m.Samples)
+	for key := range samples {
+		samples[key] = m.drawSample(rnd, rigidIDs, flexIDs)
+	}
+
+	rez := MonteCarlo{
+		Capacity: make([]float64, m.Samples),
+		Load:     make([]float64, m.Samples),
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range jobs {
+				s := &samples[key]
+				rez.Capacity[key] = sampleCapacity(baseData, rigid, flex, s)
+				rez.Load[key] = s.load
+			}
+		}()
+	}
+	for key := range samples {
+		jobs <- key
+	}
+	close(jobs)
+	wg.Wait()
+
+	var sum float64
+	for key, val := range rez.Capacity {
+		// несошедшийся или неопределённый расчёт считается отказом
+		if math.IsNaN(val) {
+			rez.Unconverged++
+			rez.Failures++
+			continue
+		}
+		sum += val
+		if rez.Load[key] >= val {
+			rez.Failures++
+		}
+	}
+	converged := m.Samples - rez.Unconverged
+	rez.CapacityMean = math.NaN()
+	if converged > 0 {
+		rez.CapacityMean = sum / float64(converged)
+	}
+	var sq float64
+	for _, val := range rez.Capacity {
+		if !math.IsNaN(val) {
+			sq += math.Pow(val-rez.CapacityMean, 2)
+		}
+	}
+	if converged > 1 {
+		rez.CapacityStdDev = math.Sqrt(sq / float64(converged-1))
+	}
+	rez.FailureProbability = float64(rez.Failures) / float64(m.Samples)
+	rez.ReliabilityIndex = -normalInverse(rez.FailureProbability)
+	return &rez, nil
+}
@@ -0,0 +1,194 @@
+package strength
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDistribution_FromNormal(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Distribution
+		u    float64
+		want float64
+	}{
+		{
+			name: "deterministic",
+			d:    Distribution{Mean: 5, StdDev: 1},
+			u:    2,
+			want: 5,
+		},
+		{
+			name: "normal",
+			d:    Distribution{Kind: Normal, Mean: 5, StdDev: 2},
+			u:    -1.5,
+			want: 2,
+		},
+		{
+			name: "lognormal median",
+			d:    Distribution{Kind: LogNormal, Mean: 1, StdDev: 0.3},
+			u:    0,
+			want: 1 / math.Sqrt(1.09),
+		},
+		{
+			name: "uniform median",
+			d:    Distribution{Kind: Uniform, Mean: 3, StdDev: 1},
+			u:    0,
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.FromNormal(tt.u); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Distribution.FromNormal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_normalInverse(t *testing.T) {
+	for _, u := range []float64{-3, -1, 0, 0.5, 2.5} {
+		if got := normalInverse(normalCDF(u)); math.Abs(got-u) > 1e-9 {
+			t.Errorf("normalInverse(normalCDF(%v)) = %v", u, got)
+		}
+	}
+}
+
+// reliabilityModel сечение из двух жёстких связей с предельным моментом 94000 кН*м без износа.
+func reliabilityModel() (BaseData, map[int]Rigid, map[int]Flex) {
+	base := BaseData{Height: []float64{0, 10}, Strain: []float64{23.5, 23.5}, ElasticModul: 2.06e8, Symmetry: true, Accuracy: 0.01}
+	rigid := map[int]Rigid{
+		1: {ID: 1, AreaStart: 20, Count: 10},
+		2: {ID: 2, AreaStart: 30, Height: 10, Count: 10},
+	}
+	return base, rigid, map[int]Flex{}
+}
+
+func TestRunMonteCarlo_seed(t *testing.T) {
+	base, rigid, flex := reliabilityModel()
+	base.Age = 20
+	rigid[1] = Rigid{ID: 1, AreaStart: 20, Corrosion: 0.2, Count: 10}
+	rigid[2] = Rigid{ID: 2, AreaStart: 30, Corrosion: 0.2, Height: 10, Count: 10}
+	model := ReliabilityModel{
+		Area:           map[int]Distribution{1: {Kind: Normal, Mean: 1, StdDev: 0.05}},
+		RigidCorrosion: map[int]Distribution{1: {Kind: LogNormal, Mean: 1, StdDev: 0.3}},
+		Wave:           Distribution{Kind: Gumbel, Mean: 50000, StdDev: 10000},
+		Samples:        200,
+		Seed:           7,
+	}
+
+	var want *MonteCarlo
+	for _, concurrency := range []int{1, 3, 8} {
+		model.Concurrency = concurrency
+		got, err := RunMonteCarlo(&base, rigid, flex, &model)
+		if err != nil {
+			t.Fatalf("RunMonteCarlo() error = %v", err)
+		}
+		if want == nil {
+			want = got
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("RunMonteCarlo() concurrency %d differs from concurrency 1", concurrency)
+		}
+	}
+	if want.CapacityStdDev == 0 {
+		t.Errorf("RunMonteCarlo() capacity does not depend on random area and corrosion")
+	}
+
+	// коррозия одной связи не влияет на предельный момент, который определяется другой связью
+	model.Area = nil
+	model.RigidCorrosion = map[int]Distribution{2: {Kind: LogNormal, Mean: 1, StdDev: 0.3}}
+	got, err := RunMonteCarlo(&base, rigid, flex, &model)
+	if err != nil {
+		t.Fatalf("RunMonteCarlo() error = %v", err)
+	}
+	if got.CapacityStdDev > 1e-6*got.CapacityMean {
+		t.Errorf("RunMonteCarlo() deck corrosion changes capacity, stddev %v", got.CapacityStdDev)
+	}
+}
+
+func TestRunMonteCarlo_probability(t *testing.T) {
+	base, rigid, flex := reliabilityModel()
+	const capacity = 94000
+	// нормальная нагрузка на одно стандартное отклонение ниже неслучайного предельного момента: Pf = Φ(-1)
+	model := ReliabilityModel{
+		StillWater: Distribution{Mean: 40000},
+		Wave:       Distribution{Kind: Normal, Mean: capacity - 40000 - 10000, StdDev: 10000},
+		Samples:    4000,
+		Seed:       1,
+	}
+	got, err := RunMonteCarlo(&base, rigid, flex, &model)
+	if err != nil {
+		t.Fatalf("RunMonteCarlo() error = %v", err)
+	}
+	if math.Abs(got.CapacityMean-capacity) > 1e-6*capacity || got.CapacityStdDev > 1e-6*capacity {
+		t.Errorf("RunMonteCarlo() capacity = %v ± %v, want %v", got.CapacityMean, got.CapacityStdDev, capacity)
+	}
+	want := normalCDF(-1)
+	// четыре стандартные ошибки оценки вероятности
+	tolerance := 4 * math.Sqrt(want*(1-want)/float64(model.Samples))
+	if math.Abs(got.FailureProbability-want) > tolerance {
+		t.Errorf("RunMonteCarlo() failure probability = %v, want %v ± %v", got.FailureProbability, want, tolerance)
+	}
+	if math.Abs(got.ReliabilityIndex-1) > 0.1 {
+		t.Errorf("RunMonteCarlo() reliability index = %v, want about 1", got.ReliabilityIndex)
+	}
+
+	model.Samples = 0
+	if _, err := RunMonteCarlo(&base, rigid, flex, &model); err == nil {
+		t.Errorf("RunMonteCarlo() no samples error expected")
+	}
+}
+
+func TestRunMonteCarlo_nonPositive(t *testing.T) {
+	base, rigid, flex := reliabilityModel()
+	// при таком разбросе нормальное распределение часто даёт отрицательные множители
+	model := ReliabilityModel{
+		Area:       map[int]Distribution{1: {Kind: Normal, Mean: 1, StdDev: 2}},
+		Yield:      Distribution{Kind: Normal, Mean: 1, StdDev: 2},
+		StillWater: Distribution{Mean: 40000},
+		Samples:    200,
+		Seed:       1,
+	}
+
+	m := model
+	m.Yield = defaultMultiplier(m.Yield)
+	rnd := rand.New(rand.NewSource(m.Seed))
+	var zeros int
+	for i := 0; i < model.Samples; i++ {
+		s := m.drawSample(rnd, []int{1}, nil)
+		if s.area[1] < 0 || s.yield < 0 {
+			t.Fatalf("drawSample() negative multiplier area %v yield %v", s.area[1], s.yield)
+		}
+		if s.yield == 0 {
+			zeros++
+		}
+	}
+	if zeros == 0 {
+		t.Errorf("drawSample() negative yield not clamped to zero")
+	}
+
+	got, err := RunMonteCarlo(&base, rigid, flex, &model)
+	if err != nil {
+		t.Fatalf("RunMonteCarlo() error = %v", err)
+	}
+	var failures, unconverged int
+	for key, val := range got.Capacity {
+		switch {
+		case math.IsNaN(val):
+			unconverged++
+			failures++
+		case got.Load[key] >= val:
+			failures++
+		}
+	}
+	if got.Failures != failures || got.Unconverged != unconverged {
+		t.Errorf("RunMonteCarlo() failures = %v unconverged = %v, want %v and %v", got.Failures, got.Unconverged, failures, unconverged)
+	}
+	if math.IsNaN(got.CapacityMean) || math.IsNaN(got.CapacityStdDev) {
+		t.Errorf("RunMonteCarlo() capacity = %v ± %v, want finite", got.CapacityMean, got.CapacityStdDev)
+	}
+}
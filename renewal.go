@@ -0,0 +1,195 @@
+package strength
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RenewalTarget требование к сечению после замены связей.
+// Задаётся либо предельный момент, либо минимальный момент сопротивления.
+type RenewalTarget struct {
+	Moment             float64 // требуемый предельный момент кН*м
+	MomentOfResistance float64 // требуемый минимальный момент сопротивления в контрольных точках см2*м
+	Length             float64 // длина района замены м
+	Density            float64 // плотность материала т/м3, если 0 то сталь
+}
+
+// Renewal план замены связей.
+type Renewal struct {
+	Elements []ElementRef // заменяемые связи
+	Weight   float64      // масса заменяемого металла т
+	Rezult   Rezult       // результат последнего приближения после замены
+}
+
+// renewalCandidate связь с износом, которую можно заменить.
+type renewalCandidate struct {
+	ref    ElementRef
+	weight float64
+}
+
+// value возвращает проверяемую величину сечения.
+func (t *RenewalTarget) value(rez *Rezult) float64 {
+	if t.Moment != 0 {
+		return rez.Moment
+	}
+	return rez.minMomentOfResistance()
+}
+
+// required возвращает требуемое значение проверяемой величины.
+func (t *RenewalTarget) required() float64 {
+	if t.Moment != 0 {
+		return t.Moment
+	}
+	return t.MomentOfResistance
+}
+
+// renewalWeight считает массу связи площадью area см2 на длине length м.
+func renewalWeight(area, length, density float64) float64 {
+	rez := area / 10000 * length * density
+	return rez
+}
+
+// findRenewalCandidates находит связи с износом, отсортированные для воспроизводимости.
+func findRenewalCandidates(rigid map[int]Rigid, flex map[int]Flex, length, density float64) []renewalCandidate {
+	rez := make([]renewalCandidate, 0)
+	for _, val := range rigid {
+		if val.AreaEnd < val.AreaStart*val.Count {
+			rez = append(rez, renewalCandidate{
+				ref:    ElementRef{Rigid: true, ID: val.ID},
+				weight: renewalWeight(val.AreaStart*val.Count, length, density),
+			})
+		}
+	}
+	for _, val := range flex {
+		if val.ThicknessEnd < val.ThicknessStart {
+			rez = append(rez, renewalCandidate{
+				ref:    ElementRef{ID: val.ID},
+				weight: renewalWeight(val.ThicknessStart/10*val.Width*val.Count, length, density),
+			})
+		}
+	}
+	sort.Slice(rez, func(i, j int) bool { return lessRef(rez[i].ref, rez[j].ref) })
+	return rez
+}
+
+// restoreElements восстанавливает перечисленные связи в копиях карт.
+func restoreElements(rigid map[int]Rigid, flex map[int]Flex, refs []ElementRef) (map[int]Rigid, map[int]Flex) {
+	newRigid := copyRigid(rigid)
+	newFlex := copyFlex(flex)
+	for _, ref := range refs {
+		if ref.Rigid {
+			r := newRigid[ref.ID]
+			r.restore()
+			newRigid[ref.ID] = r
+			continue
+		}
+		f := newFlex[ref.ID]
+		f.restore()
+		newFlex[ref.ID] = f
+	}
+	return newRigid, newFlex
+}
+
+// evalRenewal считает сечение с восстановленными связями, false если точность не достигнута.
+func evalRenewal(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex, refs []ElementRef) (Rezult, bool) {
+	newRigid, newFlex := restoreElements(rigid, flex, refs)
+	return calculateLimited(baseData, newRigid, newFlex)
+}
+
+// PlanRenewal подбирает набор связей наименьшей массы, замена которых до построечных размеров
+// обеспечивает требуемый предельный момент или момент сопротивления.
+// Связи должны быть предварительно посчитаны с учётом износа (CalcAllRigid, CalcAllFlex, ApplyGauging).
+// Подбор жадный: на каждом шаге заменяется связь с наибольшим приростом на тонну металла,
+// затем из набора исключаются связи без которых требование всё равно выполняется.
+func PlanRenewal(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex, target *RenewalTarget) (*Renewal, error) {
+	if target.Moment == 0 && target.MomentOfResistance == 0 {
+		return nil, fmt.Errorf("no renewal target")
+	}
+	if target.Length <= 0 {
+		return nil, fmt.Errorf("renewal length must be positive, got %v", target.Length)
+	}
+	density := target.Density
+	if density == 0 {
+		density = SteelDensity
+	}
+
+	data := *baseData
+	if target.Moment != 0 {
+		// для подбора по предельному моменту его и надо считать
+		data.Moment = 0
+	}
+	required := target.required()
+
+	current, ok := evalRenewal(&data, rigid, flex, nil)
+	if !ok {
+		return nil, fmt.Errorf("section not converged in %d iterations", DefaultMaxIterations)
+	}
+	if target.value(&current) >= required {
+		return &Renewal{Elements: []ElementRef{}, Rezult: current}, nil
+	}
+
+	candidates := findRenewalCandidates(rigid, flex, target.Length, density)
+	all := make([]ElementRef, len(candidates))
+	for key, val := range candidates {
+		all[key] = val.ref
+	}
+	full, ok := evalRenewal(&data, rigid, flex, all)
+	if !ok {
+		return nil, fmt.Errorf("renewed section not converged in %d iterations", DefaultMaxIterations)
+	}
+	if target.value(&full) < required {
+		return nil, fmt.Errorf("target not reachable by renewal, maximum %v, required %v", target.value(&full), required)
+	}
+
+	chosen := make([]renewalCandidate, 0)
+	refs := make([]ElementRef, 0)
+	for target.value(&current) < required && len(candidates) != 0 {
+		best := -1
+		var bestGain float64
+		var bestRezult Rezult
+		for key, val := range candidates {
+			rez, ok := evalRenewal(&data, rigid, flex, append(refs[:len(refs):len(refs)], val.ref))
+			if !ok {
+				continue
+			}
+			gain := (target.value(&rez) - target.value(&current)) / val.weight
+			if best == -1 || gain > bestGain {
+				best = key
+				bestGain = gain
+				bestRezult = rez
+			}
+		}
+		if best == -1 {
+			return nil, fmt.Errorf("no renewal candidate converged in %d iterations", DefaultMaxIterations)
+		}
+		chosen = append(chosen, candidates[best])
+		refs = append(refs, candidates[best].ref)
+		candidates = append(candidates[:best], candidates[best+1:]...)
+		current = bestRezult
+	}
+
+	// исключение лишних связей начиная с самых тяжёлых
+	sort.SliceStable(chosen, func(i, j int) bool { return chosen[i].weight > chosen[j].weight })
+	for i := 0; i < len(chosen); {
+		trial := make([]ElementRef, 0, len(chosen)-1)
+		for key, val := range chosen {
+			if key != i {
+				trial = append(trial, val.ref)
+			}
+		}
+		rez, ok := evalRenewal(&data, rigid, flex, trial)
+		if ok && target.value(&rez) >= required {
+			chosen = append(chosen[:i], chosen[i+1:]...)
+			current = rez
+			continue
+		}
+		i++
+	}
+
+	rez := Renewal{Elements: make([]ElementRef, len(chosen)), Rezult: current}
+	for key, val := range chosen {
+		rez.Elements[key] = val.ref
+		rez.Weight += val.weight
+	}
+	return &rez, nil
+}
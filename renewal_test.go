@@ -0,0 +1,114 @@
+package strength
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// renewalModel сечение с замерами толщин: изношены палубный настил и продольные балки.
+func renewalModel(t *testing.T) (BaseData, map[int]Rigid, map[int]Flex, float64) {
+	base := BaseData{Height: []float64{0, 10}, Strain: []float64{23.5, 23.5}, ElasticModul: 2.06e8, Symmetry: true, Accuracy: 0.01}
+	rigid := map[int]Rigid{
+		1: {ID: 1, AreaStart: 30, Height: 0.1, Count: 10},
+		2: {ID: 2, AreaStart: 30, Height: 9.9, Count: 10},
+	}
+	flex := map[int]Flex{
+		1: {ID: 1, Length: 240, Width: 60, ThicknessStart: 12, Count: 10},
+		2: {ID: 2, Length: 240, Width: 60, ThicknessStart: 10, Height: 10, Count: 10},
+		3: {ID: 3, Length: 60, Width: 240, ThicknessStart: 10, Height: 4, Count: 4},
+	}
+	CalcAllRigid(rigid, base.Age)
+	CalcAllFlex(flex, base.Age)
+	intact := Calculate(&base, rigid, flex).Last()
+
+	data := GaugingData{
+		Rigid: map[int]Gauging{
+			1: {ID: 1, ThicknessStart: 10, Readings: []float64{8}},
+			2: {ID: 2, ThicknessStart: 10, Readings: []float64{8}},
+		},
+		Flex: map[int]Gauging{
+			1: {ID: 1, Readings: []float64{10}},
+			2: {ID: 2, Readings: []float64{8}},
+		},
+	}
+	if err := ApplyGauging(&data, rigid, flex); err != nil {
+		t.Fatalf("ApplyGauging() error = %v", err)
+	}
+	return base, rigid, flex, intact.Moment
+}
+
+// cheapestRenewal перебором находит наименьшую массу замены, обеспечивающую предельный момент.
+func cheapestRenewal(base *BaseData, rigid map[int]Rigid, flex map[int]Flex, moment, length float64) float64 {
+	candidates := findRenewalCandidates(rigid, flex, length, SteelDensity)
+	rez := math.Inf(1)
+	for mask := 0; mask < 1<<len(candidates); mask++ {
+		refs := make([]ElementRef, 0)
+		var weight float64
+		for key, val := range candidates {
+			if mask&(1<<key) != 0 {
+				refs = append(refs, val.ref)
+				weight += val.weight
+			}
+		}
+		if weight >= rez {
+			continue
+		}
+		if got, ok := evalRenewal(base, rigid, flex, refs); ok && got.Moment >= moment {
+			rez = weight
+		}
+	}
+	return rez
+}
+
+func TestPlanRenewal(t *testing.T) {
+	base, rigid, flex, intact := renewalModel(t)
+	tests := []struct {
+		name         string
+		target       RenewalTarget
+		wantElements []ElementRef
+		wantErr      bool
+	}{
+		{
+			name:         "cheapest plates",
+			target:       RenewalTarget{Moment: 0.9 * intact, Length: 10},
+			wantElements: []ElementRef{{ID: 2}},
+		},
+		{
+			name:         "no renewal",
+			target:       RenewalTarget{Moment: 0.5 * intact, Length: 10},
+			wantElements: []ElementRef{},
+		},
+		{
+			name:    "not reachable",
+			target:  RenewalTarget{Moment: 1.1 * intact, Length: 10},
+			wantErr: true,
+		},
+		{
+			name:    "no length",
+			target:  RenewalTarget{Moment: 0.9 * intact},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PlanRenewal(&base, rigid, flex, &tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PlanRenewal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.Elements, tt.wantElements) {
+				t.Errorf("PlanRenewal() elements = %v, want %v", got.Elements, tt.wantElements)
+			}
+			if got.Rezult.Moment < tt.target.Moment {
+				t.Errorf("PlanRenewal() moment = %v, required %v", got.Rezult.Moment, tt.target.Moment)
+			}
+			want := cheapestRenewal(&base, rigid, flex, tt.target.Moment, tt.target.Length)
+			if math.Abs(got.Weight-want) > 1e-9 {
+				t.Errorf("PlanRenewal() weight = %v, cheapest %v", got.Weight, want)
+			}
+		})
+	}
+}
@@ -6,18 +6,49 @@ import (
 
 // Rezult результаты приближения.
 type Rezult struct {
-	Area                float64   // площадь сечения см2
-	StaticMoment        float64   // статический момент сечения см2*м
-	CenterOfMass        float64   // высота центра масс относительно ОП м
-	MomentOfInertia     float64   // момент инерции сечения см2*м2
-	MomentsOfResistance []float64 // моменты сопротивления в контрольных точеках см2*м
-	Moments             []float64 // предельные моменты в контрольных точеках кН*м
-	Moment              float64   // предельный момент кН*м
-	Strain              []float64 // действующие напряжения в контрольных точеках кН/см2
-	AreaLoss            float64   // потеря площади сечения корпуса см2
-	StaticMomentLoss    float64   // потеря статического момента сечения корпуса см2*м
-	MomentOfInertiaLoss float64   // потеря момента инерции сечения корпуса см2*м2
-	Heigth              []float64 // высоты контрольных точек сечения м
+	Area                       float64   // площадь сечения см2
+	StaticMoment               float64   // статический момент сечения см2*м
+	CenterOfMass               float64   // высота центра масс относительно ОП м
+	MomentOfInertia            float64   // момент инерции сечения см2*м2
+	MomentsOfResistance        []float64 // моменты сопротивления в контрольных точеках см2*м
+	Moments                    []float64 // предельные моменты в контрольных точеках кН*м
+	Moment                     float64   // предельный момент кН*м
+	Strain                     []float64 // действующие напряжения в контрольных точеках кН/см2
+	AreaLoss                   float64   // потеря площади сечения корпуса см2
+	StaticMomentLoss           float64   // потеря статического момента сечения корпуса см2*м
+	MomentOfInertiaLoss        float64   // потеря момента инерции сечения корпуса см2*м2
+	Heigth                     []float64 // высоты контрольных точек сечения м
+	HalfBreadth                float64   // полуширота центра масс относительно ДП м
+	TransverseInertia          float64   // момент инерции относительно вертикальной оси см2*м2
+	ProductOfInertia           float64   // центробежный момент инерции см2*м2
+	NeutralAxisAngle           float64   // угол наклона нейтральной оси к горизонту при вертикальном изгибе рад
+	PrincipalAngle             float64   // угол главной оси ближайшей к горизонтальной рад
+	PrincipalInertia           float64   // главный момент инерции относительно этой оси см2*м2
+	PrincipalTransverseInertia float64   // главный момент инерции относительно перпендикулярной оси см2*м2
+}
+
+// Iteration приближение расчёта.
+type Iteration struct {
+	ID     int      // номер приближения с 1
+	Rezult Rezult   // результат приближения
+	Approx []Approx // приближения пластин по возрастанию номера, отражённая на другой борт после основной, в первом приближении нет
+}
+
+// Iterations приближения расчёта по порядку.
+type Iterations []Iteration
+
+// Last возвращает результат последнего приближения.
+func (it Iterations) Last() Rezult {
+	return it[len(it)-1].Rezult
+}
+
+// minMomentOfResistance возвращает наименьший момент сопротивления в контрольных точках.
+func (r *Rezult) minMomentOfResistance() float64 {
+	rez := math.Inf(1)
+	for _, val := range r.MomentsOfResistance {
+		rez = math.Min(rez, val)
+	}
+	return rez
 }
 
 // calcLimitMoments расчитывает предельные моменты.
@@ -39,56 +70,50 @@ func calcStrain(momentOfResistance []float64, moment float64) []float64 {
 	return data
 }
 
-// calcMomentOfResistance инициализирует моменты сопротивления и рассчитывает их в на заданных высотах.
-func calcMomentOfResistance(momentOfInertia, centerOfMass float64, height []float64) []float64 {
-
-	rez := make([]float64, len(height))
-
-	for key, val := range height {
-		rez[key] = momentOfResistance(momentOfInertia, centerOfMass, val)
+// createRezult создаёт результат по суммам полного сечения за вычетом потерь.
+// Моменты сопротивления на контрольных высотах берутся наименьшими по ширине сечения.
+func createRezult(gross, loss sectionSums, breadths []float64, baseData *BaseData) (Rezult, section) {
+	net := sectionSums{
+		area:             gross.area - loss.area,
+		staticMoment:     gross.staticMoment - loss.staticMoment,
+		staticMomentY:    gross.staticMomentY - loss.staticMomentY,
+		momentOfInertia:  gross.momentOfInertia - loss.momentOfInertia,
+		momentOfInertiaY: gross.momentOfInertiaY - loss.momentOfInertiaY,
+		productOfInertia: gross.productOfInertia - loss.productOfInertia,
 	}
-	return rez
-}
-
-// createRezult создаёт результат по входным данным.
-func createRezult(area, staticMoment, momentOfInertia float64,
-	areaLoss, staticMomentLoss, momentOfInertiaLoss float64,
-	height, strain []float64,
-	simmetry bool, moment float64) Rezult {
-	var (
-		rez Rezult
-	)
-	rez.Heigth = height
-	rez.Area = area - areaLoss
-	rez.StaticMoment = staticMoment - staticMomentLoss
-	deltaMoment := momentOfInertia - momentOfInertiaLoss
-	rez.MomentOfInertia = deltaMoment - math.Pow(rez.StaticMoment, 2)/rez.Area
-
-	rez.CenterOfMass = rez.StaticMoment / rez.Area
-
-	rez.AreaLoss = areaLoss
-	rez.StaticMomentLoss = staticMomentLoss
-	rez.MomentOfInertiaLoss = momentOfInertiaLoss
-
-	if simmetry {
-		rez.Area *= 2
-		rez.StaticMoment *= 2
-		rez.MomentOfInertia *= 2
+	sec := net.central()
+
+	var rez Rezult
+	rez.Heigth = baseData.Height
+	rez.Area = sec.area
+	rez.StaticMoment = net.staticMoment
+	rez.CenterOfMass = sec.centerOfMass
+	rez.MomentOfInertia = sec.momentOfInertia
+	rez.HalfBreadth = sec.halfBreadth
+	rez.TransverseInertia = sec.transverseInertia
+	rez.ProductOfInertia = sec.productOfInertia
+	rez.NeutralAxisAngle = sec.neutralAxisAngle()
+	rez.PrincipalAngle, rez.PrincipalInertia, rez.PrincipalTransverseInertia = sec.principalAxes()
+	rez.AreaLoss = loss.area
+	rez.StaticMomentLoss = loss.staticMoment
+	rez.MomentOfInertiaLoss = loss.momentOfInertia
+
+	rez.MomentsOfResistance = make([]float64, len(baseData.Height))
+	for key, height := range baseData.Height {
+		rez.MomentsOfResistance[key] = math.Inf(1)
+		for _, y := range breadths {
+			rez.MomentsOfResistance[key] = math.Min(rez.MomentsOfResistance[key], sec.resistance(height, y))
+		}
 	}
 
-	rez.MomentsOfResistance = calcMomentOfResistance(rez.MomentOfInertia, rez.CenterOfMass, height)
-
-	if moment != 0 {
-		rez.Strain = calcStrain(rez.MomentsOfResistance, moment)
-
+	if baseData.Moment != 0 {
+		rez.Strain = calcStrain(rez.MomentsOfResistance, baseData.Moment)
 	} else {
-		rez.Moments = calcLimitMoments(strain, rez.MomentsOfResistance)
+		rez.Moments = calcLimitMoments(baseData.Strain, rez.MomentsOfResistance)
 		rez.Moment = rez.Moments[0]
 		for _, val := range rez.Moments {
 			rez.Moment = math.Min(rez.Moment, val)
 		}
 	}
-
-	return rez
-
+	return rez, sec
 }
@@ -7,6 +7,8 @@ type Rigid struct {
 	AreaStart       float64 // площадь в начале срока службы см2
 	Corrosion       float64 // годовая коррозия см2/год
 	Height          float64 // положение центра тяжести относительно ОП м
+	HalfBreadth     float64 // полуширота центра тяжести относительно ДП м, правый борт положительный
+	Side            Side    // борт на котором расположена связь
 	Count           float64 // колличество связей
 	AreaEnd         float64 // площадь в конце срока службы с учётом колличества связей см2
 	StaticMoment    float64 // статический момент см2*м
@@ -20,37 +22,26 @@ func (r *Rigid) calc(age float64) {
 	r.MomentOfInertia = calcMomentOfInertia(r.AreaEnd, r.Height)
 }
 
-// CalcAllRigid просчитать все жёсткие связи.
-func CalcAllRigid(data map[int]Rigid, age float64) {
-	for key, rigid := range data {
-		rigid.calc(age)
-		data[key] = rigid
-	}
-}
-
-// calcSumRigidArea рассчитывает суммарную площадь всех переданных связей.
-func calcSumRigidArea(data map[int]Rigid) float64 {
-	var sum float64
-	for _, val := range data {
-		sum += val.AreaEnd
-	}
-	return sum
+// restore восстанавливает связь до построечной площади.
+func (r *Rigid) restore() {
+	r.AreaEnd = r.AreaStart * r.Count
+	r.StaticMoment = calcStaticMoment(r.AreaEnd, r.Height)
+	r.MomentOfInertia = calcMomentOfInertia(r.AreaEnd, r.Height)
 }
 
-// calcSumRigidStaticMoment рассчитывает суммарный статический момент всех переданных связей.
-func calcSumRigidStaticMoment(data map[int]Rigid) float64 {
-	var sum float64
-	for _, val := range data {
-		sum += val.StaticMoment
+// copyRigid копирует карту жёстких связей.
+func copyRigid(data map[int]Rigid) map[int]Rigid {
+	rez := make(map[int]Rigid, len(data))
+	for key, val := range data {
+		rez[key] = val
 	}
-	return sum
+	return rez
 }
 
-// calcSumRigidMomentOfInertia рассчитывает суммарный момент инерции всех переданных связей.
-func calcSumRigidMomentOfInertia(data map[int]Rigid) float64 {
-	var sum float64
-	for _, val := range data {
-		sum += val.MomentOfInertia
+// CalcAllRigid просчитать все жёсткие связи.
+func CalcAllRigid(data map[int]Rigid, age float64) {
+	for key, rigid := range data {
+		rigid.calc(age)
+		data[key] = rigid
 	}
-	return sum
 }
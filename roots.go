@@ -0,0 +1,123 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// root действительный корень многочлена с кратностью.
+type root struct {
+	value        float64
+	multiplicity int
+}
+
+// rootTolerance относительная точность, с которой близкие корни считаются кратными.
+const rootTolerance = 1e-9
+
+// calcRealRoots находит все действительные корни многочлена a*x^3 + b*x^2 + c*x + d
+// по возрастанию с учётом кратности. При a = 0 решается квадратное или линейное уравнение.
+// Корни кубического уравнения находятся тригонометрической формулой Кардано и уточняются методом Ньютона.
+func calcRealRoots(a, b, c, d float64) ([]root, error) {
+	var rez []float64
+	switch {
+	case a != 0:
+		rez = calcCubicRoots(b/a, c/a, d/a)
+	case b != 0:
+		rez = calcQuadraticRoots(b, c, d)
+	case c != 0:
+		rez = []float64{-d / c}
+	default:
+		return nil, fmt.Errorf("not a polynomial equation")
+	}
+	for key, val := range rez {
+		rez[key] = polishRoot(a, b, c, d, val)
+	}
+	sort.Float64s(rez)
+	return mergeRoots(rez), nil
+}
+
+// calcCubicRoots корни приведённого уравнения x^3 + b*x^2 + c*x + d с повторением кратных.
+func calcCubicRoots(b, c, d float64) []float64 {
+	// подстановка x = t - b/3 даёт t^3 + p*t + q = 0
+	shift := b / 3
+	p := c - b*b/3
+	q := 2*b*b*b/27 - b*c/3 + d
+	disc := q*q/4 + p*p*p/27
+	scale := math.Max(q*q/4, math.Abs(p*p*p/27))
+
+	switch {
+	case scale == 0:
+		// тройной корень
+		return []float64{-shift, -shift, -shift}
+	case math.Abs(disc) <= rootTolerance*scale:
+		// простой и двойной корни
+		return []float64{3*q/p - shift, -3*q/(2*p) - shift, -3*q/(2*p) - shift}
+	case disc > 0:
+		// один действительный корень, знак выбирается без вычитания близких чисел
+		u := math.Cbrt(-q/2 - math.Copysign(math.Sqrt(disc), q))
+		return []float64{u - p/(3*u) - shift}
+	}
+	// три различных действительных корня
+	r := 2 * math.Sqrt(-p/3)
+	phi := math.Acos(math.Max(-1, math.Min(1, 3*q/(p*r))))
+	return []float64{
+		r*math.Cos(phi/3) - shift,
+		r*math.Cos((phi+2*math.Pi)/3) - shift,
+		r*math.Cos((phi+4*math.Pi)/3) - shift,
+	}
+}
+
+// calcQuadraticRoots корни уравнения a*x^2 + b*x + c с повторением кратного.
+func calcQuadraticRoots(a, b, c float64) []float64 {
+	disc := b*b - 4*a*c
+	switch {
+	case math.Abs(disc) <= rootTolerance*b*b:
+		return []float64{-b / (2 * a), -b / (2 * a)}
+	case disc < 0:
+		return nil
+	}
+	// без вычитания близких чисел
+	q := -(b + math.Copysign(math.Sqrt(disc), b)) / 2
+	if q == 0 {
+		return []float64{0, 0}
+	}
+	return []float64{q / a, c / q}
+}
+
+// polishRoot уточняет корень методом Ньютона, пока невязка уменьшается.
+func polishRoot(a, b, c, d, x float64) float64 {
+	f := func(x float64) float64 { return ((a*x+b)*x+c)*x + d }
+	df := func(x float64) float64 { return (3*a*x+2*b)*x + c }
+	fx := f(x)
+	for i := 0; i < 8 && fx != 0; i++ {
+		slope := df(x)
+		if slope == 0 {
+			break
+		}
+		next := x - fx/slope
+		fn := f(next)
+		if math.Abs(fn) >= math.Abs(fx) {
+			break
+		}
+		x, fx = next, fn
+	}
+	return x
+}
+
+// mergeRoots объединяет близкие корни отсортированного списка в кратные.
+func mergeRoots(data []float64) []root {
+	rez := make([]root, 0, len(data))
+	for _, val := range data {
+		if n := len(rez); n != 0 {
+			last := &rez[n-1]
+			if math.Abs(val-last.value) <= rootTolerance*math.Max(1, math.Abs(val)) {
+				last.value += (val - last.value) / float64(last.multiplicity+1)
+				last.multiplicity++
+				continue
+			}
+		}
+		rez = append(rez, root{value: val, multiplicity: 1})
+	}
+	return rez
+}
@@ -0,0 +1,194 @@
+package strength
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/quick"
+)
+
+func Test_calcRealRoots(t *testing.T) {
+	type args struct {
+		a, b, c, d float64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []root
+		wantErr bool
+	}{
+		// случаи и значения корней из теста прежнего решения calcCubicEquation
+		{
+			name: "Q >= 0",
+			args: args{a: 1, b: 1, c: 1, d: 1},
+			want: []root{{-1, 1}},
+		},
+		{
+			name: "Q < 0",
+			args: args{a: 1, b: -2, c: -1, d: 1},
+			want: []root{{-0.8019377358048384, 1}, {0.5549581320873711, 1}, {2.246979603717467, 1}},
+		},
+		{
+			name: "test exampl",
+			args: args{a: 1, b: -0.24, c: 0, d: -1.57},
+			want: []root{{1.2480088297460978, 1}},
+		},
+		{
+			name: "double root",
+			args: args{a: 1, b: 0, c: -3, d: 2},
+			want: []root{{-2, 1}, {1, 2}},
+		},
+		{
+			name: "triple root",
+			args: args{a: 2, b: -12, c: 24, d: -16},
+			want: []root{{2, 3}},
+		},
+		{
+			// прежнее решение возвращало ошибку, теперь решается квадратное уравнение
+			name: "not cubic equation",
+			args: args{a: 0, b: -2, c: -1, d: 1},
+			want: []root{{-1, 1}, {0.5, 1}},
+		},
+		{
+			name: "no real roots",
+			args: args{a: 0, b: 1, c: 0, d: 1},
+			want: []root{},
+		},
+		{
+			name:    "not polynomial",
+			args:    args{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calcRealRoots(tt.args.a, tt.args.b, tt.args.c, tt.args.d)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("calcRealRoots() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("calcRealRoots() = %v, want %v", got, tt.want)
+			}
+			for key, val := range got {
+				if math.Abs(val.value-tt.want[key].value) > 1e-12 || val.multiplicity != tt.want[key].multiplicity {
+					t.Errorf("calcRealRoots() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// Корни многочлена, построенного по заданным корням, восстанавливаются с кратностями и по возрастанию.
+func Test_calcRealRoots_fromRoots(t *testing.T) {
+	check := func(a, b, c int16) bool {
+		want := []float64{float64(a) / 1000, float64(b) / 1000, float64(c) / 1000}
+		sort.Float64s(want)
+		// (x - r1)(x - r2)(x - r3)
+		got, err := calcRealRoots(1,
+			-(want[0] + want[1] + want[2]),
+			want[0]*want[1]+want[1]*want[2]+want[0]*want[2],
+			-want[0]*want[1]*want[2],
+		)
+		if err != nil {
+			return false
+		}
+		var values []float64
+		for key, val := range got {
+			if key != 0 && val.value <= got[key-1].value {
+				return false
+			}
+			for i := 0; i < val.multiplicity; i++ {
+				values = append(values, val.value)
+			}
+		}
+		if len(values) != 3 {
+			return false
+		}
+		for key, val := range values {
+			if math.Abs(val-want[key]) > 1e-6*math.Max(1, math.Abs(want[key])) {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(check, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+	// кратные корни
+	for _, val := range [][3]int16{{1000, 1000, -2000}, {1500, 1500, 1500}, {0, 0, 0}, {-7, 3000, 3000}} {
+		if !check(val[0], val[1], val[2]) {
+			t.Errorf("calcRealRoots() wrong roots for %v", val)
+		}
+	}
+}
+
+// Найденные корни произвольного многочлена обращают его в ноль, а их число совпадает со сменой знака.
+func Test_calcRealRoots_residual(t *testing.T) {
+	check := func(a, b, c, d float64) bool {
+		if a == 0 || math.IsInf(a, 0) {
+			return true
+		}
+		// приведение к разумному масштабу
+		a, b, c, d = 1, math.Mod(b/a, 100), math.Mod(c/a, 100), math.Mod(d/a, 100)
+		got, err := calcRealRoots(a, b, c, d)
+		if err != nil || len(got) == 0 {
+			return false
+		}
+		f := func(x float64) float64 { return ((a*x+b)*x+c)*x + d }
+		df := func(x float64) float64 { return (3*a*x+2*b)*x + c }
+		scale := func(x float64) float64 {
+			return math.Abs(a*x*x*x) + math.Abs(b*x*x) + math.Abs(c*x) + math.Abs(d)
+		}
+		for _, val := range got {
+			x := val.value
+			if math.Abs(f(x)) > 1e-9*scale(x) && math.Abs(df(x)) > 1e-6 {
+				return false
+			}
+		}
+
+		// знак меняется только в корнях нечётной кратности, близкие корни по знаку не различить
+		var odd int
+		for key, val := range got {
+			if key != 0 && val.value-got[key-1].value < 1e-3 {
+				return true
+			}
+			odd += val.multiplicity % 2
+		}
+		// все корни лежат внутри границы Коши, к равномерной сетке добавляются середины между корнями,
+		// чтобы между соседними точками был не больше чем один корень
+		bound := 1 + math.Max(math.Abs(b), math.Max(math.Abs(c), math.Abs(d)))
+		const steps = 4000
+		points := make([]float64, 0, steps+len(got)+1)
+		for i := 0; i <= steps; i++ {
+			points = append(points, -bound+2*bound*float64(i)/steps)
+		}
+		for key := 1; key < len(got); key++ {
+			points = append(points, (got[key-1].value+got[key].value)/2)
+		}
+		sort.Float64s(points)
+		var changes int
+		var sign float64
+		for _, x := range points {
+			y := f(x)
+			if math.Abs(y) <= 1e-9*scale(x) {
+				continue
+			}
+			if sign != 0 && math.Signbit(y) != math.Signbit(sign) {
+				changes++
+			}
+			sign = y
+		}
+		return changes == odd
+	}
+	if err := quick.Check(check, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(mergeRoots([]float64{1, 1, 2}), []root{{1, 2}, {2, 1}}) {
+		t.Errorf("mergeRoots() wrong multiplicities")
+	}
+}
@@ -0,0 +1,202 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Scantlings условия подбора связей минимальной площади.
+type Scantlings struct {
+	Thicknesses []float64    // доступные толщины листов мм
+	Profiles    []float64    // доступные площади профилей см2
+	Moment      float64      // требуемый предельный момент кН*м, если 0 то не проверяется
+	Fixed       []ElementRef // связи размеры которых не меняются
+}
+
+// Design результат подбора связей.
+type Design struct {
+	Rigid      map[int]Rigid // подобранные жёсткие связи
+	Flex       map[int]Flex  // подобранные гибкие связи
+	Area       float64       // построечная площадь сечения см2
+	Iterations int           // количество просчётов сечения
+	Rezult     Rezult        // результат последнего приближения подобранного сечения
+}
+
+// designVariable переменная проектирования: связь и индекс выбранного размера.
+type designVariable struct {
+	ref   ElementRef
+	index int
+}
+
+// calcDesignArea считает построечную площадь сечения.
+func calcDesignArea(rigid map[int]Rigid, flex map[int]Flex) float64 {
+	var sum compensatedSum
+	for _, id := range RigidIDs(rigid) {
+		sum.add(rigid[id].AreaStart * rigid[id].Count)
+	}
+	for _, id := range FlexIDs(flex) {
+		sum.add(flex[id].ThicknessStart / 10 * flex[id].Width * flex[id].Count)
+	}
+	return sum.value()
+}
+
+// checkDesign проверяет сечение по предельному моменту и допускаемым напряжениям.
+// Напряжения проверяются если в исходных данных задан расчётный момент.
+// Сечение, расчёт которого не сошёлся, считается не удовлетворяющим требованиям.
+func checkDesign(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex, moment float64) (Rezult, bool) {
+	for _, val := range flex {
+		if val.ThicknessEnd <= 0 {
+			return Rezult{}, false
+		}
+	}
+	for _, val := range rigid {
+		if val.AreaEnd <= 0 {
+			return Rezult{}, false
+		}
+	}
+
+	var rez Rezult
+	if baseData.Moment != 0 {
+		var ok bool
+		rez, ok = calculateLimited(baseData, rigid, flex)
+		if !ok {
+			return rez, false
+		}
+		for key, val := range rez.Strain {
+			if math.Abs(val) > baseData.Strain[key] {
+				return rez, false
+			}
+		}
+	}
+	if moment != 0 {
+		data := *baseData
+		data.Moment = 0
+		limit, ok := calculateLimited(&data, rigid, flex)
+		if !ok || limit.Moment < moment {
+			return limit, false
+		}
+		if baseData.Moment == 0 {
+			rez = limit
+		}
+	}
+	return rez, true
+}
+
+// setSize задаёт связи размер из доступного ряда и пересчитывает её на срок службы.
+func (s *Scantlings) setSize(rigid map[int]Rigid, flex map[int]Flex, v designVariable, age float64) {
+	if v.ref.Rigid {
+		r := rigid[v.ref.ID]
+		r.AreaStart = s.Profiles[v.index]
+		r.calc(age)
+		rigid[v.ref.ID] = r
+		return
+	}
+	f := flex[v.ref.ID]
+	f.ThicknessStart = s.Thicknesses[v.index]
+	f.calc(age)
+	flex[v.ref.ID] = f
+}
+
+// isFixed проверяет, что связь не подбирается.
+func (s *Scantlings) isFixed(ref ElementRef) bool {
+	for _, val := range s.Fixed {
+		if val == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// OptimizeScantlings подбирает построечные толщины гибких связей и площади жёстких связей
+// из доступного ряда так, чтобы площадь сечения была минимальной при выполнении
+// требования по предельному моменту и допускаемым напряжениям.
+// Подбор начинается с наибольших размеров, затем связи по одной уменьшаются на шаг ряда,
+// пока хоть одно уменьшение не нарушает требований (покоординатный спуск).
+func OptimizeScantlings(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex, s *Scantlings) (*Design, error) {
+	if s.Moment == 0 && baseData.Moment == 0 {
+		return nil, fmt.Errorf("no strength constraint")
+	}
+	// ряды сортируются в копии, чтобы не менять переданные данные
+	sorted := *s
+	sorted.Thicknesses = append([]float64(nil), s.Thicknesses...)
+	sorted.Profiles = append([]float64(nil), s.Profiles...)
+	sort.Float64s(sorted.Thicknesses)
+	sort.Float64s(sorted.Profiles)
+	s = &sorted
+
+	newRigid := copyRigid(rigid)
+	newFlex := copyFlex(flex)
+	vars := make([]designVariable, 0)
+	for _, val := range rigid {
+		ref := ElementRef{Rigid: true, ID: val.ID}
+		if !s.isFixed(ref) && len(s.Profiles) != 0 {
+			vars = append(vars, designVariable{ref: ref, index: len(s.Profiles) - 1})
+		}
+	}
+	for _, val := range flex {
+		ref := ElementRef{ID: val.ID}
+		if !s.isFixed(ref) && len(s.Thicknesses) != 0 {
+			vars = append(vars, designVariable{ref: ref, index: len(s.Thicknesses) - 1})
+		}
+	}
+	if len(vars) == 0 {
+		return nil, fmt.Errorf("no design variables")
+	}
+	sort.Slice(vars, func(i, j int) bool { return lessRef(vars[i].ref, vars[j].ref) })
+	for _, v := range vars {
+		s.setSize(newRigid, newFlex, v, baseData.Age)
+	}
+
+	iterations := 1
+	rez, ok := checkDesign(baseData, newRigid, newFlex, s.Moment)
+	if !ok {
+		return nil, fmt.Errorf("maximum scantlings do not satisfy strength constraints")
+	}
+
+	for changed := true; changed; {
+		changed = false
+		// сначала пробуются связи дающие наибольшую экономию площади за шаг
+		sort.SliceStable(vars, func(i, j int) bool {
+			return s.stepSaving(newRigid, newFlex, vars[i]) > s.stepSaving(newRigid, newFlex, vars[j])
+		})
+		for key, v := range vars {
+			if v.index == 0 {
+				continue
+			}
+			trial := designVariable{ref: v.ref, index: v.index - 1}
+			s.setSize(newRigid, newFlex, trial, baseData.Age)
+			iterations++
+			trialRez, ok := checkDesign(baseData, newRigid, newFlex, s.Moment)
+			if !ok {
+				s.setSize(newRigid, newFlex, v, baseData.Age)
+				continue
+			}
+			vars[key] = trial
+			rez = trialRez
+			changed = true
+		}
+	}
+
+	design := Design{
+		Rigid:      newRigid,
+		Flex:       newFlex,
+		Area:       calcDesignArea(newRigid, newFlex),
+		Iterations: iterations,
+		Rezult:     rez,
+	}
+	return &design, nil
+}
+
+// stepSaving считает уменьшение построечной площади при уменьшении связи на шаг ряда.
+func (s *Scantlings) stepSaving(rigid map[int]Rigid, flex map[int]Flex, v designVariable) float64 {
+	if v.index == 0 {
+		return 0
+	}
+	if v.ref.Rigid {
+		r := rigid[v.ref.ID]
+		return (s.Profiles[v.index] - s.Profiles[v.index-1]) * r.Count
+	}
+	f := flex[v.ref.ID]
+	return (s.Thicknesses[v.index] - s.Thicknesses[v.index-1]) / 10 * f.Width * f.Count
+}
@@ -0,0 +1,94 @@
+package strength
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestOptimizeScantlings(t *testing.T) {
+	base := BaseData{Height: []float64{0, 10}, Strain: []float64{23.5, 23.5}, ElasticModul: 2.06e8, Symmetry: true, Moment: 2e5, Accuracy: 0.01}
+	rigid := map[int]Rigid{
+		1: {ID: 1, AreaStart: 30, Height: 0.1, Count: 10},
+		2: {ID: 2, AreaStart: 30, Height: 9.9, Count: 10},
+	}
+	flex := map[int]Flex{
+		1: {ID: 1, Length: 240, Width: 60, ThicknessStart: 12, Count: 10},
+		2: {ID: 2, Length: 240, Width: 60, ThicknessStart: 12, Height: 10, Count: 10},
+		3: {ID: 3, Length: 60, Width: 240, ThicknessStart: 10, Height: 4, Count: 4},
+	}
+	CalcAllRigid(rigid, base.Age)
+	CalcAllFlex(flex, base.Age)
+	s := Scantlings{
+		Thicknesses: []float64{14, 6, 8, 12, 10},
+		Moment:      3e5,
+		Fixed:       []ElementRef{{Rigid: true, ID: 1}, {Rigid: true, ID: 2}, {ID: 3}},
+	}
+
+	got, err := OptimizeScantlings(&base, rigid, flex, &s)
+	if err != nil {
+		t.Fatalf("OptimizeScantlings() error = %v", err)
+	}
+	if _, ok := checkDesign(&base, got.Rigid, got.Flex, s.Moment); !ok {
+		t.Errorf("OptimizeScantlings() design does not satisfy constraints: %+v", got.Rezult)
+	}
+	if len(got.Rezult.Strain) != len(base.Strain) {
+		t.Fatalf("OptimizeScantlings() strains = %v", got.Rezult.Strain)
+	}
+	for key, val := range got.Rezult.Strain {
+		if math.Abs(val) > base.Strain[key] {
+			t.Errorf("OptimizeScantlings() strain %v exceeds %v", val, base.Strain[key])
+		}
+	}
+
+	sorted := []float64{6, 8, 10, 12, 14}
+	// перебор всех сочетаний толщин днища и палубы
+	minArea := math.Inf(1)
+	for _, bottom := range s.Thicknesses {
+		for _, deck := range s.Thicknesses {
+			trial := copyFlex(flex)
+			for id, thickness := range map[int]float64{1: bottom, 2: deck} {
+				f := trial[id]
+				f.ThicknessStart = thickness
+				f.calc(base.Age)
+				trial[id] = f
+			}
+			area := calcDesignArea(rigid, trial)
+			if area >= minArea {
+				continue
+			}
+			if _, ok := checkDesign(&base, rigid, trial, s.Moment); ok {
+				minArea = area
+			}
+		}
+	}
+	if got.Area != minArea {
+		t.Errorf("OptimizeScantlings() area = %v, minimum %v", got.Area, minArea)
+	}
+	// уменьшение любой подбираемой толщины на шаг ряда нарушает требования
+	for id := 1; id <= 2; id++ {
+		f := got.Flex[id]
+		index := sort.SearchFloat64s(sorted, f.ThicknessStart)
+		if index == 0 {
+			continue
+		}
+		trial := copyFlex(got.Flex)
+		f.ThicknessStart = sorted[index-1]
+		f.calc(base.Age)
+		trial[id] = f
+		if _, ok := checkDesign(&base, got.Rigid, trial, s.Moment); ok {
+			t.Errorf("OptimizeScantlings() plate %d thickness %v can be reduced", id, got.Flex[id].ThicknessStart)
+		}
+	}
+	if got.Flex[3].ThicknessStart != 10 || got.Rigid[1].AreaStart != 30 {
+		t.Errorf("OptimizeScantlings() fixed elements changed")
+	}
+	if flex[1].ThicknessStart != 12 || s.Thicknesses[0] != 14 {
+		t.Errorf("OptimizeScantlings() input data changed")
+	}
+
+	s.Moment = 1e7
+	if _, err := OptimizeScantlings(&base, rigid, flex, &s); err == nil {
+		t.Errorf("OptimizeScantlings() unreachable moment error expected")
+	}
+}
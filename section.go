@@ -0,0 +1,242 @@
+package strength
+
+import (
+	"math"
+)
+
+// member связь полного сечения с учётом её поперечного положения.
+type member struct {
+	ref         ElementRef
+	key         int     // ключ в приближении: номер связи, для отражённой на другой борт минус номер
+	area        float64 // площадь с учётом количества связей см2
+	height      float64 // высота центра тяжести относительно ОП м
+	halfBreadth float64 // полуширота центра тяжести относительно ДП м
+	flex        *Flex   // гибкая связь, nil для жёсткой
+}
+
+// sectionSums суммы площадей и моментов полного сечения относительно ОП и ДП.
+type sectionSums struct {
+	area             float64 // см2
+	staticMoment     float64 // относительно ОП см2*м
+	staticMomentY    float64 // относительно ДП см2*м
+	momentOfInertia  float64 // относительно ОП см2*м2
+	momentOfInertiaY float64 // относительно ДП см2*м2
+	productOfInertia float64 // относительно ОП и ДП см2*м2
+}
+
+// compensatedSum сумма с компенсацией ошибки округления (Ноймайер),
+// результат не зависит от разброса порядков слагаемых.
+type compensatedSum struct {
+	sum, compensation float64
+}
+
+// add добавляет слагаемое.
+func (c *compensatedSum) add(val float64) {
+	t := c.sum + val
+	if math.Abs(c.sum) >= math.Abs(val) {
+		c.compensation += (c.sum - t) + val
+	} else {
+		c.compensation += (val - t) + c.sum
+	}
+	c.sum = t
+}
+
+// value значение суммы.
+func (c *compensatedSum) value() float64 {
+	return c.sum + c.compensation
+}
+
+// sumsAccumulator накапливает суммы площадей и моментов с компенсацией округления.
+type sumsAccumulator struct {
+	area, staticMoment, staticMomentY, momentOfInertia, momentOfInertiaY, productOfInertia compensatedSum
+}
+
+// add добавляет площадь в точке сечения.
+func (s *sumsAccumulator) add(area, height, halfBreadth float64) {
+	s.area.add(area)
+	s.staticMoment.add(calcStaticMoment(area, height))
+	s.staticMomentY.add(calcStaticMoment(area, halfBreadth))
+	s.momentOfInertia.add(calcMomentOfInertia(area, height))
+	s.momentOfInertiaY.add(calcMomentOfInertia(area, halfBreadth))
+	s.productOfInertia.add(area * height * halfBreadth)
+}
+
+// sums накопленные суммы.
+func (s *sumsAccumulator) sums() sectionSums {
+	return sectionSums{
+		area:             s.area.value(),
+		staticMoment:     s.staticMoment.value(),
+		staticMomentY:    s.staticMomentY.value(),
+		momentOfInertia:  s.momentOfInertia.value(),
+		momentOfInertiaY: s.momentOfInertiaY.value(),
+		productOfInertia: s.productOfInertia.value(),
+	}
+}
+
+// Side борт на котором расположена связь.
+type Side int
+
+const (
+	// SideDefault связь половины сечения при симметрии, иначе связь полного сечения на своей полушироте.
+	SideDefault Side = iota
+	// SideBoth пара одинаковых связей на обоих бортах, отражается и без симметрии.
+	SideBoth
+	// SideCentre связь на ДП, учитывается один раз.
+	SideCentre
+	// SidePort связь только на левом борту.
+	SidePort
+	// SideStarboard связь только на правом борту.
+	SideStarboard
+)
+
+// placements возвращает полушироты на которых связь входит в полное сечение.
+func placements(side Side, halfBreadth float64, symmetry bool) []float64 {
+	switch side {
+	case SideBoth:
+		return []float64{math.Abs(halfBreadth), -math.Abs(halfBreadth)}
+	case SideCentre:
+		return []float64{0}
+	case SidePort:
+		return []float64{-math.Abs(halfBreadth)}
+	case SideStarboard:
+		return []float64{math.Abs(halfBreadth)}
+	}
+	if symmetry {
+		return []float64{halfBreadth, -halfBreadth}
+	}
+	return []float64{halfBreadth}
+}
+
+// buildMembers собирает связи полного сечения.
+// Если symmetry, то связи без указания борта задают половину сечения и отражаются на другой борт,
+// связи на ДП учитываются один раз.
+func buildMembers(rigid map[int]Rigid, flex map[int]Flex, symmetry bool) []member {
+	rez := make([]member, 0, 2*(len(rigid)+len(flex)))
+	for _, id := range RigidIDs(rigid) {
+		r := rigid[id]
+		for key, y := range placements(r.Side, r.HalfBreadth, symmetry) {
+			rez = append(rez, member{
+				ref:         ElementRef{Rigid: true, ID: id},
+				key:         memberKey(id, key),
+				area:        r.AreaEnd,
+				height:      r.Height,
+				halfBreadth: y,
+			})
+		}
+	}
+	for _, id := range FlexIDs(flex) {
+		f := flex[id]
+		for key, y := range placements(f.Side, f.HalfBreadth, symmetry) {
+			rez = append(rez, member{
+				ref:         ElementRef{ID: id},
+				key:         memberKey(id, key),
+				area:        f.AreaEnd,
+				height:      f.Height,
+				halfBreadth: y,
+				flex:        &f,
+			})
+		}
+	}
+	return rez
+}
+
+// memberKey ключ связи в приближении, у отражённой на другой борт минус номер.
+func memberKey(id, placement int) int {
+	if placement == 0 {
+		return id
+	}
+	return -id
+}
+
+// calcMemberSums считает суммы по связям сечения в порядке связей.
+func calcMemberSums(members []member) sectionSums {
+	var rez sumsAccumulator
+	for _, m := range members {
+		rez.add(m.area, m.height, m.halfBreadth)
+	}
+	return rez.sums()
+}
+
+// symmetryTolerance относительная величина центробежного момента инерции, ниже которой сечение считается симметричным.
+const symmetryTolerance = 1e-12
+
+// section центральные характеристики полного сечения при косом изгибе.
+type section struct {
+	area              float64
+	centerOfMass      float64 // высота центра масс м
+	halfBreadth       float64 // полуширота центра масс м
+	momentOfInertia   float64 // относительно горизонтальной центральной оси см2*м2
+	transverseInertia float64 // относительно вертикальной центральной оси см2*м2
+	productOfInertia  float64 // центробежный см2*м2
+}
+
+// central переводит суммы к центральным осям.
+func (s *sectionSums) central() section {
+	var rez section
+	rez.area = s.area
+	rez.centerOfMass = s.staticMoment / s.area
+	rez.halfBreadth = s.staticMomentY / s.area
+	rez.momentOfInertia = s.momentOfInertia - math.Pow(s.staticMoment, 2)/s.area
+	rez.transverseInertia = s.momentOfInertiaY - math.Pow(s.staticMomentY, 2)/s.area
+	rez.productOfInertia = s.productOfInertia - s.staticMoment*s.staticMomentY/s.area
+	// у симметричного сечения центробежный момент остаётся ненулевым только из-за округлений
+	if math.Abs(rez.productOfInertia) < symmetryTolerance*math.Sqrt(rez.momentOfInertia*rez.transverseInertia) {
+		rez.productOfInertia = 0
+	}
+	return rez
+}
+
+// resistance момент сопротивления в точке сечения при вертикальном изгибе с учётом
+// поворота нейтральной оси у несимметричного сечения.
+func (s *section) resistance(height, halfBreadth float64) float64 {
+	if s.productOfInertia == 0 {
+		return momentOfResistance(s.momentOfInertia, s.centerOfMass, height)
+	}
+	det := s.momentOfInertia*s.transverseInertia - math.Pow(s.productOfInertia, 2)
+	lever := s.transverseInertia*(height-s.centerOfMass) - s.productOfInertia*(halfBreadth-s.halfBreadth)
+	return det / math.Abs(lever)
+}
+
+// strain действующее напряжение в точке сечения, растяжение положительно.
+// momentFlag false прогиб, true перегиб.
+func (s *section) strain(height, halfBreadth, moment float64, momentFlag bool) float64 {
+	if s.productOfInertia == 0 {
+		return calcActualStrain(height, moment, s.centerOfMass, s.momentOfInertia, momentFlag)
+	}
+	if !momentFlag {
+		moment = -moment
+	}
+	det := s.momentOfInertia*s.transverseInertia - math.Pow(s.productOfInertia, 2)
+	lever := s.transverseInertia*(height-s.centerOfMass) - s.productOfInertia*(halfBreadth-s.halfBreadth)
+	return moment * lever / det
+}
+
+// neutralAxisAngle угол наклона нейтральной оси к горизонту при вертикальном изгибе.
+func (s *section) neutralAxisAngle() float64 {
+	if s.productOfInertia == 0 {
+		return 0
+	}
+	return math.Atan(s.productOfInertia / s.transverseInertia)
+}
+
+// principalAxes считает угол главной оси ближайшей к горизонтальной и главные моменты инерции
+// относительно неё и перпендикулярной ей оси.
+func (s *section) principalAxes() (angle, inertia, transverseInertia float64) {
+	if s.productOfInertia != 0 {
+		angle = 0.5 * math.Atan(2*s.productOfInertia/(s.transverseInertia-s.momentOfInertia))
+	}
+	sin, cos := math.Sincos(angle)
+	inertia = s.momentOfInertia*cos*cos - 2*s.productOfInertia*sin*cos + s.transverseInertia*sin*sin
+	transverseInertia = s.momentOfInertia + s.transverseInertia - inertia
+	return angle, inertia, transverseInertia
+}
+
+// controlBreadths возвращает крайние полушироты связей и ДП, в них проверяются контрольные высоты.
+func controlBreadths(members []member) []float64 {
+	minY, maxY := 0.0, 0.0
+	for _, m := range members {
+		minY = math.Min(minY, m.halfBreadth)
+		maxY = math.Max(maxY, m.halfBreadth)
+	}
+	return []float64{minY, 0, maxY}
+}
@@ -0,0 +1,131 @@
+package strength
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_placements(t *testing.T) {
+	type args struct {
+		side        Side
+		halfBreadth float64
+		symmetry    bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want []float64
+	}{
+		{
+			name: "half section",
+			args: args{SideDefault, 3, true},
+			want: []float64{3, -3},
+		},
+		{
+			name: "full section",
+			args: args{SideDefault, -3, false},
+			want: []float64{-3},
+		},
+		{
+			name: "centreline once",
+			args: args{SideCentre, 3, true},
+			want: []float64{0},
+		},
+		{
+			name: "both sides in full section",
+			args: args{SideBoth, -3, false},
+			want: []float64{3, -3},
+		},
+		{
+			name: "port only",
+			args: args{SidePort, 3, true},
+			want: []float64{-3},
+		},
+		{
+			name: "starboard only",
+			args: args{SideStarboard, -3, false},
+			want: []float64{3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := placements(tt.args.side, tt.args.halfBreadth, tt.args.symmetry); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("placements() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_section_principalAxes(t *testing.T) {
+	// два равных сосредоточенных пояса по диагонали
+	members := []member{
+		{area: 100, height: 0, halfBreadth: 0},
+		{area: 100, height: 2, halfBreadth: 2},
+	}
+	sums := calcMemberSums(members)
+	sec := sums.central()
+	if sec.productOfInertia != 200 {
+		t.Fatalf("section.productOfInertia = %v, want %v", sec.productOfInertia, 200)
+	}
+	angle, inertia, transverse := sec.principalAxes()
+	if math.Abs(angle-math.Pi/4) > 1e-12 {
+		t.Errorf("section.principalAxes() angle = %v, want %v", angle, math.Pi/4)
+	}
+	if math.Abs(inertia) > 1e-9 || math.Abs(transverse-400) > 1e-9 {
+		t.Errorf("section.principalAxes() inertia = %v, %v, want 0, 400", inertia, transverse)
+	}
+}
+
+func TestCalculate_centreline(t *testing.T) {
+	base := BaseData{Height: []float64{0, 10}, Strain: []float64{23.5, 23.5}, Symmetry: true, Accuracy: 0.1}
+	rigid := map[int]Rigid{
+		1: {ID: 1, AreaStart: 100, Height: 0, Count: 1, Side: SideCentre},
+		2: {ID: 2, AreaStart: 50, Height: 10, HalfBreadth: 5, Count: 1},
+	}
+	CalcAllRigid(rigid, base.Age)
+	got := Calculate(&base, rigid, map[int]Flex{}).Last()
+	if got.Area != 200 {
+		t.Errorf("Calculate() area = %v, want %v", got.Area, 200)
+	}
+	if got.CenterOfMass != 5 {
+		t.Errorf("Calculate() center of mass = %v, want %v", got.CenterOfMass, 5)
+	}
+	if got.ProductOfInertia != 0 {
+		t.Errorf("Calculate() product of inertia = %v, want 0", got.ProductOfInertia)
+	}
+}
+
+func Test_compensatedSum(t *testing.T) {
+	var sum compensatedSum
+	for _, val := range []float64{1, 1e100, 1, -1e100} {
+		sum.add(val)
+	}
+	if got := sum.value(); got != 2 {
+		t.Errorf("compensatedSum.value() = %v, want 2", got)
+	}
+}
+
+func TestCalculate_deterministic(t *testing.T) {
+	base, rigid, flex := convergenceModel()
+	want := Calculate(&base, rigid, flex)
+	for i := 0; i < 20; i++ {
+		// карты заново заполняются, порядок их обхода каждый раз другой
+		r := make(map[int]Rigid)
+		for key, val := range rigid {
+			r[key] = val
+		}
+		f := make(map[int]Flex)
+		for key, val := range flex {
+			f[key] = val
+		}
+		if got := Calculate(&base, r, f); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Calculate() differs between runs")
+		}
+	}
+	for key, val := range want[1].Approx {
+		if key != 0 && val.ID < want[1].Approx[key-1].ID {
+			t.Errorf("Calculate() approximations not ordered by ID")
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Parameter параметр связи по которому считается чувствительность.
+type Parameter int
+
+const (
+	// ParamSize построечная толщина гибкой связи мм или площадь жёсткой связи см2.
+	ParamSize Parameter = iota
+	// ParamHeight положение центра тяжести связи м.
+	ParamHeight
+	// ParamCorrosion годовая коррозия.
+	ParamCorrosion
+)
+
+// String возвращает название параметра.
+func (p Parameter) String() string {
+	switch p {
+	case ParamSize:
+		return "размер"
+	case ParamHeight:
+		return "высота"
+	case ParamCorrosion:
+		return "коррозия"
+	}
+	return fmt.Sprintf("Parameter(%d)", int(p))
+}
+
+// Sensitivity чувствительность результата к одному параметру одной связи.
+type Sensitivity struct {
+	Element   ElementRef // связь
+	Name      string     // имя связи
+	Parameter Parameter  // параметр
+	Value     float64    // значение параметра
+	Moment    float64    // производная предельного момента по параметру
+	Strain    []float64  // производные напряжений в контрольных точках по параметру, если задан расчётный момент
+	Relative  float64    // относительная чувствительность предельного момента dM/dx*x/M
+}
+
+// defaultSensitivityStep относительный шаг численного дифференцирования.
+const defaultSensitivityStep = 0.01
+
+// getParameter возвращает значение параметра связи.
+func getParameter(rigid map[int]Rigid, flex map[int]Flex, ref ElementRef, p Parameter) float64 {
+	if ref.Rigid {
+		r := rigid[ref.ID]
+		switch p {
+		case ParamHeight:
+			return r.Height
+		case ParamCorrosion:
+			return r.Corrosion
+		}
+		return r.AreaStart
+	}
+	f := flex[ref.ID]
+	switch p {
+	case ParamHeight:
+		return f.Height
+	case ParamCorrosion:
+		return f.Corrosion
+	}
+	return f.ThicknessStart
+}
+
+// setParameter задаёт значение параметра связи и пересчитывает её на срок службы.
+func setParameter(rigid map[int]Rigid, flex map[int]Flex, ref ElementRef, p Parameter, val, age float64) {
+	if ref.Rigid {
+		r := rigid[ref.ID]
+		switch p {
+		case ParamHeight:
+			r.Height = val
+		case ParamCorrosion:
+			r.Corrosion = val
+		default:
+			r.AreaStart = val
+		}
+		r.calc(age)
+		rigid[ref.ID] = r
+		return
+	}
+	f := flex[ref.ID]
+	switch p {
+	case ParamHeight:
+		f.Height = val
+	case ParamCorrosion:
+		f.Corrosion = val
+	default:
+		f.ThicknessStart = val
+	}
+	f.calc(age)
+	flex[ref.ID] = f
+}
+
+// evalSensitivity считает предельный момент и, если задан расчётный момент, напряжения.
+// Если расчёт не сошёлся за DefaultMaxIterations приближений, возвращается ошибка.
+func evalSensitivity(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex) (float64, []float64, error) {
+	var strain []float64
+	if baseData.Moment != 0 {
+		rez, ok := calculateLimited(baseData, rigid, flex)
+		if !ok {
+			return 0, nil, fmt.Errorf("stresses not converged in %d iterations", DefaultMaxIterations)
+		}
+		strain = rez.Strain
+	}
+	data := *baseData
+	data.Moment = 0
+	rez, ok := calculateLimited(&data, rigid, flex)
+	if !ok {
+		return 0, nil, fmt.Errorf("ultimate moment not converged in %d iterations", DefaultMaxIterations)
+	}
+	return rez.Moment, strain, nil
+}
+
+// CalcSensitivity считает центральными разностями производные предельного момента и напряжений
+// по размеру, высоте и коррозии каждой связи и возвращает их по убыванию относительной чувствительности.
+// step относительный шаг, если 0 то 1%; для нулевых параметров шаг берётся абсолютным.
+// Связи пересчитываются на срок службы по скорости коррозии, поэтому замеры толщин не учитываются.
+func CalcSensitivity(baseData *BaseData, rigid map[int]Rigid, flex map[int]Flex, step float64) ([]Sensitivity, error) {
+	if step == 0 {
+		step = defaultSensitivityStep
+	}
+	if step < 0 {
+		return nil, fmt.Errorf("bad sensitivity step %v", step)
+	}
+
+	newRigid := copyRigid(rigid)
+	newFlex := copyFlex(flex)
+	CalcAllRigid(newRigid, baseData.Age)
+	CalcAllFlex(newFlex, baseData.Age)
+	moment, _, err := evalSensitivity(baseData, newRigid, newFlex)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]ElementRef, 0, len(rigid)+len(flex))
+	for id := range rigid {
+		refs = append(refs, ElementRef{Rigid: true, ID: id})
+	}
+	for id := range flex {
+		refs = append(refs, ElementRef{ID: id})
+	}
+	sort.Slice(refs, func(i, j int) bool { return lessRef(refs[i], refs[j]) })
+
+	rez := make([]Sensitivity, 0, len(refs)*3)
+	for _, ref := range refs {
+		name := flex[ref.ID].Name
+		if ref.Rigid {
+			name = rigid[ref.ID].Name
+		}
+		for _, p := range []Parameter{ParamSize, ParamHeight, ParamCorrosion} {
+			val := getParameter(newRigid, newFlex, ref, p)
+			h := step * math.Abs(val)
+			if h == 0 {
+				h = step
+			}
+
+			setParameter(newRigid, newFlex, ref, p, val+h, baseData.Age)
+			momentPlus, strainPlus, err := evalSensitivity(baseData, newRigid, newFlex)
+			if err != nil {
+				return nil, fmt.Errorf("element %d parameter %d: %v", ref.ID, int(p), err)
+			}
+			setParameter(newRigid, newFlex, ref, p, val-h, baseData.Age)
+			momentMinus, strainMinus, err := evalSensitivity(baseData, newRigid, newFlex)
+			if err != nil {
+				return nil, fmt.Errorf("element %d parameter %d: %v", ref.ID, int(p), err)
+			}
+			setParameter(newRigid, newFlex, ref, p, val, baseData.Age)
+
+			s := Sensitivity{
+				Element:   ref,
+				Name:      name,
+				Parameter: p,
+				Value:     val,
+				Moment:    (momentPlus - momentMinus) / (2 * h),
+			}
+			if strainPlus != nil {
+				s.Strain = make([]float64, len(strainPlus))
+				for key := range strainPlus {
+					s.Strain[key] = (strainPlus[key] - strainMinus[key]) / (2 * h)
+				}
+			}
+			if moment != 0 {
+				s.Relative = s.Moment * val / moment
+			}
+			rez = append(rez, s)
+		}
+	}
+
+	sort.SliceStable(rez, func(i, j int) bool {
+		return math.Abs(rez[i].Relative) > math.Abs(rez[j].Relative)
+	})
+	return rez, nil
+}
@@ -0,0 +1,87 @@
+package strength
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalcSensitivity(t *testing.T) {
+	// Две жёсткие связи без редуцирования: предельный момент по нижней точке M = σ·h2·A1,
+	// напряжение в нижней точке σ = M/(h2·A1), где A1 площадь нижних связей с учётом симметрии.
+	base := BaseData{Height: []float64{0, 10}, Strain: []float64{23.5, 23.5}, ElasticModul: 2.06e8, Symmetry: true, Moment: 47000, Accuracy: 0.01}
+	rigid := map[int]Rigid{
+		1: {ID: 1, Name: "днище", AreaStart: 20, Count: 10},
+		2: {ID: 2, Name: "палуба", AreaStart: 30, Height: 10, Count: 10},
+	}
+	const factor = 20 // количество связей с учётом симметрии
+	area := rigid[1].AreaStart * factor
+
+	got, err := CalcSensitivity(&base, rigid, map[int]Flex{}, 0)
+	if err != nil {
+		t.Fatalf("CalcSensitivity() error = %v", err)
+	}
+	if len(got) != 6 {
+		t.Fatalf("CalcSensitivity() = %d values, want 6", len(got))
+	}
+
+	tests := []struct {
+		name       string
+		ref        ElementRef
+		p          Parameter
+		wantMoment float64
+		wantStrain float64 // производная напряжения в нижней точке
+	}{
+		{
+			name:       "bottom area",
+			ref:        ElementRef{Rigid: true, ID: 1},
+			p:          ParamSize,
+			wantMoment: 23.5 * 10 * factor,
+			wantStrain: -base.Moment / (10 * area * area) * factor,
+		},
+		{
+			name:       "deck height",
+			ref:        ElementRef{Rigid: true, ID: 2},
+			p:          ParamHeight,
+			wantMoment: 23.5 * area,
+			wantStrain: -base.Moment / (10 * 10 * area),
+		},
+		{
+			name: "deck area",
+			ref:  ElementRef{Rigid: true, ID: 2},
+			p:    ParamSize,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s *Sensitivity
+			for key := range got {
+				if got[key].Element == tt.ref && got[key].Parameter == tt.p {
+					s = &got[key]
+				}
+			}
+			if s == nil {
+				t.Fatalf("CalcSensitivity() missing %v %v", tt.ref, tt.p)
+			}
+			if math.Abs(s.Moment-tt.wantMoment) > 1e-6*math.Max(1, math.Abs(tt.wantMoment)) {
+				t.Errorf("CalcSensitivity() moment derivative = %v, want %v", s.Moment, tt.wantMoment)
+			}
+			// центральная разность для 1/x отличается от производной на h² = 1e-4
+			if math.Abs(s.Strain[0]-tt.wantStrain) > 1e-3*math.Max(1e-3, math.Abs(tt.wantStrain)) {
+				t.Errorf("CalcSensitivity() strain derivative = %v, want %v", s.Strain[0], tt.wantStrain)
+			}
+		})
+	}
+
+	// M линеен по A1 и h2, поэтому их относительная чувствительность равна 1
+	for key, val := range got {
+		if key != 0 && math.Abs(val.Relative) > math.Abs(got[key-1].Relative) {
+			t.Errorf("CalcSensitivity() not sorted: %v after %v", val.Relative, got[key-1].Relative)
+		}
+	}
+	for _, val := range got[:2] {
+		top := val.Parameter == ParamSize && val.Element.ID == 1 || val.Parameter == ParamHeight && val.Element.ID == 2
+		if !top || math.Abs(val.Relative-1) > 1e-9 {
+			t.Errorf("CalcSensitivity() first = %v %v relative %v, want bottom area or deck height with 1", val.Element, val.Parameter, val.Relative)
+		}
+	}
+}
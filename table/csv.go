@@ -0,0 +1,117 @@
+package table
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// record строка CSV с номером строки в исходном файле для сообщений об ошибках.
+type record struct {
+	fields []string
+	line   int
+}
+
+// readRecords читает CSV, строки начинающиеся с # считаются комментариями.
+// Строки разбираются по одной, чтобы ошибки указывали номер строки в файле
+// с учётом пропущенных комментариев и пустых строк.
+func readRecords(r io.Reader) ([]record, error) {
+	var rez []record
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		reader := csv.NewReader(strings.NewReader(text))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		fields, err := reader.Read()
+		if err != nil {
+			if perr, ok := err.(*csv.ParseError); ok {
+				err = perr.Err
+			}
+			return nil, fmt.Errorf("table: line %d: %v", line, err)
+		}
+		rez = append(rez, record{fields: fields, line: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rez, nil
+}
+
+// parseRow разбирает числа строки CSV.
+func parseRow(record []string, line int) ([]float64, error) {
+	rez := make([]float64, len(record))
+	for key, val := range record {
+		var err error
+		rez[key], err = strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil {
+			return nil, fmt.Errorf("table: line %d column %d: %v", line, key+1, err)
+		}
+	}
+	return rez, nil
+}
+
+// isHeader true если в первом столбце строки не число.
+func isHeader(record []string) bool {
+	_, err := strconv.ParseFloat(strings.TrimSpace(record[0]), 64)
+	return err != nil
+}
+
+// Read1D читает одномерную таблицу из CSV: в строке аргумент и значение.
+// Первая строка может быть заголовком.
+func Read1D(r io.Reader, method Method) (*Table1D, error) {
+	records, err := readRecords(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > 0 && isHeader(records[0].fields) {
+		records = records[1:]
+	}
+	x := make([]float64, 0, len(records))
+	y := make([]float64, 0, len(records))
+	for _, rec := range records {
+		if len(rec.fields) != 2 {
+			return nil, fmt.Errorf("table: line %d has %d columns, want 2", rec.line, len(rec.fields))
+		}
+		row, err := parseRow(rec.fields, rec.line)
+		if err != nil {
+			return nil, err
+		}
+		x = append(x, row[0])
+		y = append(y, row[1])
+	}
+	return New1D(x, y, method)
+}
+
+// Read2D читает двумерную таблицу из CSV.
+// Первая строка: подпись и аргументы y, следующие строки: аргумент x и значения для каждого y.
+func Read2D(r io.Reader) (*Table2D, error) {
+	records, err := readRecords(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("table: need header and at least one row")
+	}
+	y, err := parseRow(records[0].fields[1:], records[0].line)
+	if err != nil {
+		return nil, err
+	}
+	x := make([]float64, 0, len(records)-1)
+	z := make([][]float64, 0, len(records)-1)
+	for _, rec := range records[1:] {
+		row, err := parseRow(rec.fields, rec.line)
+		if err != nil {
+			return nil, err
+		}
+		x = append(x, row[0])
+		z = append(z, row[1:])
+	}
+	return New2D(x, y, z)
+}
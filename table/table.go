@@ -0,0 +1,212 @@
+// Package table интерполяция по табличным зависимостям: одномерные таблицы с линейной
+// и монотонной кубической интерполяцией и двумерные таблицы с билинейной интерполяцией.
+package table
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Method способ интерполяции одномерной таблицы.
+type Method int
+
+const (
+	// Linear линейная интерполяция.
+	Linear Method = iota
+	// MonotoneCubic монотонная кубическая интерполяция Фрича-Карлсона, не даёт выбросов между узлами.
+	MonotoneCubic
+)
+
+// Policy поведение за пределами таблицы.
+type Policy int
+
+const (
+	// Clamp значение на границе таблицы.
+	Clamp Policy = iota
+	// Extrapolate продолжение крайнего участка.
+	Extrapolate
+	// Error ошибка.
+	Error
+)
+
+// Table1D одномерная таблица y(x).
+type Table1D struct {
+	X, Y   []float64 // узлы по возрастанию x
+	Method Method    // способ интерполяции
+	Below  Policy    // поведение левее первого узла
+	Above  Policy    // поведение правее последнего узла
+	slopes []float64 // производные в узлах для кубической интерполяции
+}
+
+// New1D создаёт одномерную таблицу, за пределами таблицы берутся граничные значения.
+func New1D(x, y []float64, method Method) (*Table1D, error) {
+	if len(x) != len(y) {
+		return nil, fmt.Errorf("table: %d arguments and %d values", len(x), len(y))
+	}
+	err := checkAxis(x)
+	if err != nil {
+		return nil, err
+	}
+	t := Table1D{X: x, Y: y, Method: method}
+	switch method {
+	case Linear:
+	case MonotoneCubic:
+		t.slopes = monotoneSlopes(x, y)
+	default:
+		return nil, fmt.Errorf("table: unknown method %d", method)
+	}
+	return &t, nil
+}
+
+// checkAxis проверяет что узлов не меньше двух и они строго возрастают.
+func checkAxis(x []float64) error {
+	if len(x) < 2 {
+		return fmt.Errorf("table: need at least 2 points")
+	}
+	for i := 1; i < len(x); i++ {
+		if !(x[i] > x[i-1]) {
+			return fmt.Errorf("table: arguments not increasing at %d", i)
+		}
+	}
+	return nil
+}
+
+// locate находит участок [x[i], x[i+1]] для аргумента с учётом поведения за пределами.
+// При Clamp аргумент заменяется граничным узлом.
+func locate(x []float64, val float64, below, above Policy) (i int, arg float64, err error) {
+	n := len(x)
+	switch {
+	case math.IsNaN(val):
+		return 0, 0, fmt.Errorf("table: argument is NaN")
+	case val < x[0]:
+		switch below {
+		case Clamp:
+			return 0, x[0], nil
+		case Extrapolate:
+			return 0, val, nil
+		}
+		return 0, 0, fmt.Errorf("table: %v below range %v", val, x[0])
+	case val > x[n-1]:
+		switch above {
+		case Clamp:
+			return n - 2, x[n-1], nil
+		case Extrapolate:
+			return n - 2, val, nil
+		}
+		return 0, 0, fmt.Errorf("table: %v above range %v", val, x[n-1])
+	case val == x[n-1]:
+		return n - 2, val, nil
+	}
+	i = sort.Search(n, func(k int) bool { return x[k] > val }) - 1
+	return i, val, nil
+}
+
+// linear линейная интерполяция между двумя точками.
+func linear(key, firstKey, firstVal, secondKey, secondVal float64) float64 {
+	val := firstVal + (secondVal-firstVal)/(secondKey-firstKey)*(key-firstKey)
+	return val
+}
+
+// At значение таблицы для аргумента x.
+func (t *Table1D) At(x float64) (float64, error) {
+	i, arg, err := locate(t.X, x, t.Below, t.Above)
+	if err != nil {
+		return 0, err
+	}
+	if arg == t.X[i] {
+		return t.Y[i], nil
+	}
+	if arg == t.X[i+1] {
+		return t.Y[i+1], nil
+	}
+	if t.Method == Linear {
+		return linear(arg, t.X[i], t.Y[i], t.X[i+1], t.Y[i+1]), nil
+	}
+	// за пределами таблицы кубика продолжается касательной в граничном узле
+	if arg < t.X[0] {
+		return t.Y[0] + t.slopes[0]*(arg-t.X[0]), nil
+	}
+	if n := len(t.X); arg > t.X[n-1] {
+		return t.Y[n-1] + t.slopes[n-1]*(arg-t.X[n-1]), nil
+	}
+	return hermite(arg, t.X[i], t.Y[i], t.slopes[i], t.X[i+1], t.Y[i+1], t.slopes[i+1]), nil
+}
+
+// hermite кубический многочлен Эрмита на участке по значениям и производным в концах.
+func hermite(x, x0, y0, m0, x1, y1, m1 float64) float64 {
+	h := x1 - x0
+	s := (x - x0) / h
+	s2 := s * s
+	s3 := s2 * s
+	rez := (2*s3-3*s2+1)*y0 + (s3-2*s2+s)*h*m0 + (-2*s3+3*s2)*y1 + (s3-s2)*h*m1
+	return rez
+}
+
+// monotoneSlopes производные в узлах по Фричу-Карлсону: в экстремумах нулевые,
+// внутри монотонных участков взвешенное гармоническое среднее наклонов соседних участков.
+func monotoneSlopes(x, y []float64) []float64 {
+	n := len(x)
+	h := make([]float64, n-1)
+	d := make([]float64, n-1)
+	for i := range h {
+		h[i] = x[i+1] - x[i]
+		d[i] = (y[i+1] - y[i]) / h[i]
+	}
+	rez := make([]float64, n)
+	rez[0] = d[0]
+	rez[n-1] = d[n-2]
+	for i := 1; i < n-1; i++ {
+		if d[i-1]*d[i] <= 0 {
+			continue
+		}
+		w1 := 2*h[i] + h[i-1]
+		w2 := h[i] + 2*h[i-1]
+		rez[i] = (w1 + w2) / (w1/d[i-1] + w2/d[i])
+	}
+	return rez
+}
+
+// Table2D двумерная таблица z(x, y) с билинейной интерполяцией.
+type Table2D struct {
+	X, Y  []float64   // узлы по возрастанию
+	Z     [][]float64 // значения, Z[i][j] соответствует X[i] и Y[j]
+	Below Policy      // поведение левее первых узлов по обоим аргументам
+	Above Policy      // поведение правее последних узлов по обоим аргументам
+}
+
+// New2D создаёт двумерную таблицу, за пределами таблицы берутся граничные значения.
+func New2D(x, y []float64, z [][]float64) (*Table2D, error) {
+	err := checkAxis(x)
+	if err != nil {
+		return nil, err
+	}
+	err = checkAxis(y)
+	if err != nil {
+		return nil, err
+	}
+	if len(z) != len(x) {
+		return nil, fmt.Errorf("table: %d rows for %d arguments", len(z), len(x))
+	}
+	for key, val := range z {
+		if len(val) != len(y) {
+			return nil, fmt.Errorf("table: row %d has %d values for %d arguments", key, len(val), len(y))
+		}
+	}
+	return &Table2D{X: x, Y: y, Z: z}, nil
+}
+
+// At значение таблицы для аргументов x, y.
+func (t *Table2D) At(x, y float64) (float64, error) {
+	i, argX, err := locate(t.X, x, t.Below, t.Above)
+	if err != nil {
+		return 0, err
+	}
+	j, argY, err := locate(t.Y, y, t.Below, t.Above)
+	if err != nil {
+		return 0, err
+	}
+	z0 := linear(argY, t.Y[j], t.Z[i][j], t.Y[j+1], t.Z[i][j+1])
+	z1 := linear(argY, t.Y[j], t.Z[i+1][j], t.Y[j+1], t.Z[i+1][j+1])
+	return linear(argX, t.X[i], z0, t.X[i+1], z1), nil
+}
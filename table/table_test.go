@@ -0,0 +1,178 @@
+package table
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTable1D_At(t *testing.T) {
+	x := []float64{0, 1, 2, 4}
+	y := []float64{0, 1, 1, 3}
+	tests := []struct {
+		name    string
+		method  Method
+		below   Policy
+		above   Policy
+		arg     float64
+		want    float64
+		wantErr bool
+	}{
+		{name: "node", arg: 1, want: 1},
+		{name: "last node", arg: 4, want: 3},
+		{name: "linear", arg: 3, want: 2},
+		{name: "clamp below", arg: -1, want: 0},
+		{name: "clamp above", arg: 5, want: 3},
+		{name: "extrapolate below", below: Extrapolate, arg: -1, want: -1},
+		{name: "extrapolate above", above: Extrapolate, arg: 5, want: 4},
+		{name: "error below", below: Error, arg: -1, wantErr: true},
+		{name: "error above", above: Error, arg: 5, wantErr: true},
+		{name: "NaN", arg: math.NaN(), wantErr: true},
+		{name: "cubic node", method: MonotoneCubic, arg: 2, want: 1},
+		{name: "cubic flat part", method: MonotoneCubic, arg: 1.5, want: 1},
+		{name: "cubic extrapolate", method: MonotoneCubic, above: Extrapolate, arg: 5, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab, err := New1D(x, y, tt.method)
+			if err != nil {
+				t.Fatalf("New1D() error = %v", err)
+			}
+			tab.Below, tab.Above = tt.below, tt.above
+			got, err := tab.At(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Table1D.At() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Table1D.At() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTable1D_monotone(t *testing.T) {
+	// ступенька, на которой обычный кубический сплайн даёт выбросы
+	x := []float64{0, 1, 2, 3, 4, 5}
+	y := []float64{0, 0, 0, 1, 1, 1}
+	tab, err := New1D(x, y, MonotoneCubic)
+	if err != nil {
+		t.Fatalf("New1D() error = %v", err)
+	}
+	prev := 0.0
+	for arg := 0.0; arg <= 5; arg += 0.01 {
+		got, _ := tab.At(arg)
+		if got < prev-1e-15 || got < 0 || got > 1 {
+			t.Fatalf("Table1D.At(%v) = %v not monotone after %v", arg, got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestNew1D(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+	}{
+		{name: "one point", x: []float64{1}, y: []float64{1}},
+		{name: "length mismatch", x: []float64{1, 2}, y: []float64{1}},
+		{name: "not increasing", x: []float64{1, 1}, y: []float64{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New1D(tt.x, tt.y, Linear); err == nil {
+				t.Errorf("New1D() error = nil")
+			}
+		})
+	}
+}
+
+func TestTable2D_At(t *testing.T) {
+	// плоскость z = x + 2y восстанавливается билинейной интерполяцией точно
+	x := []float64{0, 1, 3}
+	y := []float64{0, 2}
+	z := [][]float64{{0, 4}, {1, 5}, {3, 7}}
+	tests := []struct {
+		name    string
+		policy  Policy
+		x, y    float64
+		want    float64
+		wantErr bool
+	}{
+		{name: "node", x: 1, y: 2, want: 5},
+		{name: "inside", x: 2, y: 0.5, want: 3},
+		{name: "clamp", x: 4, y: -1, want: 3},
+		{name: "extrapolate", policy: Extrapolate, x: 4, y: -1, want: 2},
+		{name: "error", policy: Error, x: 2, y: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab, err := New2D(x, y, z)
+			if err != nil {
+				t.Fatalf("New2D() error = %v", err)
+			}
+			tab.Below, tab.Above = tt.policy, tt.policy
+			got, err := tab.At(tt.x, tt.y)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Table2D.At() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Table2D.At() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRead1D(t *testing.T) {
+	data := "# комментарий\nx,y\n1,10\n2, 20\n"
+	tab, err := Read1D(strings.NewReader(data), Linear)
+	if err != nil {
+		t.Fatalf("Read1D() error = %v", err)
+	}
+	if got, _ := tab.At(1.5); got != 15 {
+		t.Errorf("Table1D.At() = %v, want 15", got)
+	}
+
+	if tab, err := Read1D(strings.NewReader("1,10\r\n2,20\r\n"), Linear); err != nil {
+		t.Errorf("Read1D() CRLF error = %v", err)
+	} else if got, _ := tab.At(1.5); got != 15 {
+		t.Errorf("Table1D.At() CRLF = %v, want 15", got)
+	}
+
+	// номер строки в ошибке считается по файлу с заголовком и комментариями
+	errTests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "bad value", data: "1,10\n2,x\n", want: "line 2 column 2"},
+		{name: "extra column", data: "1,10,3\n2,20\n", want: "line 1 has 3 columns"},
+		{name: "header", data: "x,y\n1,10\n2,x\n", want: "line 3 column 2"},
+		{name: "comments", data: "# таблица\nx,y\n\n# данные\n1,10\n2,20,3\n", want: "line 6 has 3 columns"},
+		{name: "quote", data: "x,y\n1,\"10\n", want: "line 2:"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read1D(strings.NewReader(tt.data), Linear)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Read1D() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRead2D(t *testing.T) {
+	data := "x/y,0,2\n0,0,4\n1,1,5\n"
+	tab, err := Read2D(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read2D() error = %v", err)
+	}
+	if got, _ := tab.At(0.5, 1); got != 2.5 {
+		t.Errorf("Table2D.At() = %v, want 2.5", got)
+	}
+
+	if _, err := Read2D(strings.NewReader("x/y,0,2\n0,0\n")); err == nil {
+		t.Errorf("Read2D() short row error = nil")
+	}
+}
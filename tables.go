@@ -0,0 +1,26 @@
+package strength
+
+import (
+	_ "embed" // табличные данные встраиваются в пакет
+	"strings"
+
+	"github.com/kenits/strength/table"
+)
+
+//go:embed data/kappa.csv
+var kappaCSV string
+
+// kappaTable коэффициент kappa по отношению сторон пластины,
+// отношение меньше 1 ошибка, больше 5 принимается 5.
+var kappaTable = mustTable1D(kappaCSV, table.Linear, table.Error, table.Clamp)
+
+// mustTable1D читает встроенную таблицу, ошибка во встроенных данных недопустима.
+func mustTable1D(data string, method table.Method, below, above table.Policy) *table.Table1D {
+	t, err := table.Read1D(strings.NewReader(data), method)
+	if err != nil {
+		panic(err)
+	}
+	t.Below = below
+	t.Above = above
+	return t
+}